@@ -0,0 +1,550 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/config"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
+)
+
+// push.autoSetupRemote, which igitt passes on every push, needs git 2.37.
+var minimumGitVersion = []int{2, 37, 0}
+
+type findingStatus int
+
+const (
+	statusOk findingStatus = iota
+	statusInfo
+	statusWarning
+	statusProblem
+)
+
+// finding is the result of a single check. fix is nil when the problem can
+// only be solved by hand, then suggestion says how.
+type finding struct {
+	title      string
+	status     findingStatus
+	detail     string
+	suggestion string
+	fixTitle   string
+	fix        func() error
+}
+
+// isFixable tells whether --fix repairs the finding, only warnings and
+// problems are changed.
+func (f finding) isFixable() bool {
+	return f.fix != nil && f.status >= statusWarning
+}
+
+func (f finding) getSymbol() string {
+	switch f.status {
+	case statusOk:
+		return color.HiGreenString(plain.Label("✓", locale.Get("plain.ok")))
+	case statusInfo:
+		return color.HiBlueString(plain.Label("i", locale.Get("plain.info")))
+	case statusWarning:
+		return color.HiYellowString(plain.Label("!", locale.Get("plain.warning")))
+	}
+	return color.HiRedString(plain.Label("✗", locale.Get("plain.failed")))
+}
+
+var gitVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+func parseGitVersion(output string) ([]int, bool) {
+	match := gitVersionPattern.FindStringSubmatch(output)
+	if match == nil {
+		return nil, false
+	}
+
+	var version []int
+	for _, part := range match[1:] {
+		number, _ := strconv.Atoi(part)
+		version = append(version, number)
+	}
+
+	return version, true
+}
+
+func formatVersion(version []int) string {
+	var parts []string
+	for _, part := range version {
+		parts = append(parts, strconv.Itoa(part))
+	}
+	return strings.Join(parts, ".")
+}
+
+func checkGit() []finding {
+	path, err := exec.LookPath("git")
+	if err != nil {
+		return []finding{{
+			title:      locale.Get("doctor.git"),
+			status:     statusProblem,
+			detail:     locale.Get("doctor.git.notFound"),
+			suggestion: locale.Get("doctor.git.install"),
+		}}
+	}
+
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return []finding{{
+			title:      locale.Get("doctor.git"),
+			status:     statusProblem,
+			detail:     locale.Get("doctor.git.versionFailed", path, err),
+			suggestion: locale.Get("doctor.git.reinstall"),
+		}}
+	}
+
+	version, found := parseGitVersion(string(output))
+	if !found {
+		return []finding{{
+			title:  locale.Get("doctor.git"),
+			status: statusWarning,
+			detail: locale.Get("doctor.git.unknownVersion", path, strings.TrimSpace(string(output))),
+		}}
+	}
+
+	if slices.Compare(version, minimumGitVersion) < 0 {
+		return []finding{{
+			title:      locale.Get("doctor.git"),
+			status:     statusProblem,
+			detail:     locale.Get("doctor.git.tooOld", path, formatVersion(version), formatVersion(minimumGitVersion)),
+			suggestion: locale.Get("doctor.git.update"),
+		}}
+	}
+
+	return []finding{{
+		title:  locale.Get("doctor.git"),
+		status: statusOk,
+		detail: locale.Get("doctor.git.version", path, formatVersion(version)),
+	}}
+}
+
+func checkConfigFile(configPath string) finding {
+	problems, err := config.ValidateConfigFile(configPath)
+	if err != nil {
+		return finding{
+			title:      locale.Get("doctor.config"),
+			status:     statusProblem,
+			detail:     locale.Get("doctor.config.unreadable", configPath, err),
+			suggestion: locale.Get("doctor.config.checkPermissions"),
+		}
+	}
+
+	if len(problems) == 0 {
+		return finding{title: locale.Get("doctor.config"), status: statusOk, detail: configPath}
+	}
+
+	var details []string
+	for _, problem := range problems {
+		details = append(details, problem.String())
+	}
+
+	return finding{
+		title:      locale.Get("doctor.config"),
+		status:     statusProblem,
+		detail:     configPath + "\n" + strings.Join(details, "\n"),
+		suggestion: locale.Get("doctor.config.fixLines"),
+		fixTitle:   locale.Get("doctor.config.disableUnknown"),
+		fix: func() error {
+			disabled, err := config.DisableUnknownKeys(configPath)
+			if err != nil {
+				return err
+			}
+			if len(disabled) == 0 {
+				return errors.New(locale.Get("doctor.config.noUnknownKeys"))
+			}
+			printDetail(locale.Get("doctor.config.disabled", strings.Join(disabled, ", ")))
+			return nil
+		},
+	}
+}
+
+func checkConfig() []finding {
+	findings := []finding{checkConfigFile(config.GetConfigPath(false))}
+
+	if repoConfigPath := config.GetRepoConfigPath(); repoConfigPath != "" {
+		findings = append(findings, checkConfigFile(repoConfigPath))
+	}
+
+	return findings
+}
+
+func checkLogFile() []finding {
+	logPath, err := logger.GetLogPath()
+	if err == nil {
+		var file *os.File
+		file, err = os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err == nil {
+			file.Close()
+			return []finding{{title: locale.Get("doctor.log"), status: statusOk, detail: logPath}}
+		}
+	}
+
+	return []finding{{
+		title:      locale.Get("doctor.log"),
+		status:     statusWarning,
+		detail:     locale.Get("doctor.log.notWritable", logPath, err),
+		suggestion: locale.Get("doctor.log.suggestion"),
+	}}
+}
+
+func checkAlias() []finding {
+	aliasPath, err := GetAliasScriptPath()
+	if err != nil {
+		return []finding{{title: locale.Get("doctor.alias"), status: statusWarning, detail: err.Error()}}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return []finding{{title: locale.Get("doctor.alias"), status: statusWarning, detail: err.Error()}}
+	}
+
+	aliases := FindAliases()
+
+	// the alias is optional, --fix does not create one that was never asked
+	// for
+	if len(aliases) == 0 {
+		return []finding{{
+			title:      locale.Get("doctor.alias"),
+			status:     statusInfo,
+			detail:     locale.Get("doctor.alias.missing", aliasPath),
+			suggestion: locale.Get("doctor.alias.install"),
+		}}
+	}
+
+	var findings []finding
+
+	for _, alias := range aliases {
+		var detail string
+		switch {
+		case alias.IsStale():
+			detail = locale.Get("doctor.alias.stale", alias.Path, alias.Target)
+		case alias.Target != executable:
+			detail = locale.Get("doctor.alias.different", alias.Path, alias.Target)
+		default:
+			findings = append(findings, finding{title: locale.Get("doctor.alias"), status: statusOk, detail: alias.Path})
+			continue
+		}
+
+		findings = append(findings, finding{
+			title:      locale.Get("doctor.alias"),
+			status:     statusWarning,
+			detail:     detail,
+			suggestion: locale.Get("doctor.alias.update"),
+			fixTitle:   locale.Get("doctor.alias.fix"),
+			fix: func() error {
+				return InstallAlias(filepath.Dir(alias.Path), alias.Kind)
+			},
+		})
+	}
+
+	return findings
+}
+
+func isUtf8Locale() bool {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("WT_SESSION") != "" || os.Getenv("TERM_PROGRAM") != ""
+	}
+
+	for _, variable := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(variable); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+
+	return false
+}
+
+// supportsEmoji can only guess from the terminal program, there is no way to
+// ask a terminal whether it has an emoji font.
+func supportsEmoji() bool {
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "Apple_Terminal", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+
+	return os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != ""
+}
+
+func setIconType(iconType string) func() error {
+	return func() error {
+		configPath := config.GetConfigPath(false)
+		if _, err := config.SetValue(configPath, "iconType", iconType); err != nil {
+			return err
+		}
+		printDetail(locale.Get("doctor.iconTypeSet", iconType, configPath))
+		return nil
+	}
+}
+
+func checkTerminal() []finding {
+	var findings []finding
+
+	colors := finding{title: locale.Get("doctor.colors"), status: statusOk, detail: locale.Get("doctor.colors.supported")}
+	if os.Getenv("COLORTERM") == "truecolor" || os.Getenv("COLORTERM") == "24bit" {
+		colors.detail = locale.Get("doctor.colors.trueColor")
+	}
+	if color.NoColor {
+		colors.status = statusInfo
+		colors.detail = locale.Get("doctor.colors.off")
+	}
+	findings = append(findings, colors)
+
+	// an invalid configuration is reported by checkConfig
+	igittConfig, _ := config.LoadConfig()
+	iconType := igittConfig.IconType
+	utf8 := isUtf8Locale()
+
+	unicode := finding{title: locale.Get("doctor.unicode"), status: statusOk, detail: locale.Get("doctor.unicode.ok")}
+	if !utf8 {
+		unicode.status = statusInfo
+		unicode.detail = locale.Get("doctor.unicode.missing")
+
+		if iconType != "ascii" {
+			unicode.status = statusWarning
+			unicode.suggestion = locale.Get("doctor.unicode.suggestion")
+			unicode.fixTitle = locale.Get("doctor.unicode.fix")
+			unicode.fix = setIconType("ascii")
+		}
+	}
+	findings = append(findings, unicode)
+
+	emoji := finding{title: locale.Get("doctor.emoji"), status: statusOk, detail: locale.Get("doctor.emoji.ok")}
+	if !supportsEmoji() {
+		emoji.status = statusInfo
+		emoji.detail = locale.Get("doctor.emoji.unknown")
+
+		// without UTF-8 the Unicode finding switches to the ascii icons, the
+		// unicode ones would not show up either
+		if iconType == "emoji" && utf8 {
+			emoji.status = statusWarning
+			emoji.suggestion = locale.Get("doctor.emoji.suggestion")
+			emoji.fixTitle = locale.Get("doctor.emoji.fix")
+			emoji.fix = setIconType("unicode")
+		}
+	}
+	findings = append(findings, emoji)
+
+	nerdFont := finding{
+		title:  locale.Get("doctor.nerdFont"),
+		status: statusInfo,
+		detail: locale.Get("doctor.nerdFont.unknown"),
+	}
+	if iconType == "nerdfont" {
+		nerdFont.detail = locale.Get("doctor.nerdFont.enabled")
+	}
+	findings = append(findings, nerdFont)
+
+	return findings
+}
+
+func getGitConfig(key string) string {
+	output, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func setGitIdentity() error {
+	name := getGitConfig("user.name")
+	email := getGitConfig("user.email")
+	currentName, currentEmail := name, email
+
+	// accessible mode does not prefill the fields, an empty answer keeps
+	// what is set
+	nameDescription := "\n  " + locale.Get("doctor.identity.nameDescription") + "\n"
+	emailDescription := "\n  " + locale.Get("doctor.identity.emailDescription") + "\n"
+	if plain.Enabled && currentName != "" {
+		nameDescription += locale.Get("settings.plain.keep", currentName) + "\n"
+	}
+	if plain.Enabled && currentEmail != "" {
+		emailDescription += locale.Get("settings.plain.keep", currentEmail) + "\n"
+	}
+
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(plain.Title(locale.Get("doctor.identity.name"), nameDescription)).
+				Description(nameDescription).
+				Validate(func(s string) error {
+					if plain.Enabled && s == "" && currentName != "" {
+						return nil
+					}
+					if strings.TrimSpace(s) == "" {
+						return errors.New(locale.Get("doctor.identity.enterName"))
+					}
+					return nil
+				}).
+				Value(&name),
+			huh.NewInput().
+				Title(plain.Title(locale.Get("doctor.identity.email"), emailDescription)).
+				Description(emailDescription).
+				Validate(func(s string) error {
+					if plain.Enabled && s == "" && currentEmail != "" {
+						return nil
+					}
+					if !strings.Contains(s, "@") {
+						return errors.New(locale.Get("doctor.identity.enterEmail"))
+					}
+					return nil
+				}).
+				Value(&email),
+		),
+	).WithTheme(theme.GetHuhTheme()).WithAccessible(plain.Enabled).Run()
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = currentName
+	}
+	if email == "" {
+		email = currentEmail
+	}
+
+	for key, value := range map[string]string{"user.name": name, "user.email": email} {
+		if output, err := exec.Command("git", "config", "--global", key, value).CombinedOutput(); err != nil {
+			return errors.New(locale.Get("doctor.identity.setFailed", key, strings.TrimSpace(string(output))))
+		}
+	}
+
+	printDetail(locale.Get("doctor.identity.set", name, email))
+	return nil
+}
+
+func checkIdentity() []finding {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil
+	}
+
+	name := getGitConfig("user.name")
+	email := getGitConfig("user.email")
+
+	if name != "" && email != "" {
+		return []finding{{title: locale.Get("doctor.identity"), status: statusOk, detail: fmt.Sprintf("%s <%s>", name, email)}}
+	}
+
+	detail := locale.Get("doctor.identity.missing")
+	if name != "" {
+		detail = locale.Get("doctor.identity.noEmail")
+	} else if email != "" {
+		detail = locale.Get("doctor.identity.noName")
+	}
+
+	return []finding{{
+		title:      locale.Get("doctor.identity"),
+		status:     statusProblem,
+		detail:     detail,
+		suggestion: locale.Get("doctor.identity.suggestion"),
+		fixTitle:   locale.Get("doctor.identity.fix"),
+		fix:        setGitIdentity,
+	}}
+}
+
+// printDetail indents a line below the title of a finding.
+func printDetail(text string) {
+	if plain.Enabled {
+		fmt.Printf("  %s\n", text)
+		return
+	}
+	fmt.Printf("  %-14s %s\n", "", text)
+}
+
+func printFinding(f finding, fix bool) {
+	detailLines := strings.Split(f.detail, "\n")
+
+	// the labels of plain output differ in width, columns would not line up
+	if plain.Enabled {
+		fmt.Printf("%s %s: %s\n", f.getSymbol(), f.title, detailLines[0])
+	} else {
+		fmt.Printf("%s %-14s %s\n", f.getSymbol(), f.title, detailLines[0])
+	}
+
+	for _, line := range detailLines[1:] {
+		printDetail(line)
+	}
+
+	if f.status == statusOk {
+		return
+	}
+
+	if f.suggestion != "" {
+		printDetail(color.HiBlackString(plain.Label("→", locale.Get("plain.suggestion")) + " " + f.suggestion))
+	}
+
+	if f.isFixable() && !fix {
+		printDetail(color.HiBlackString(plain.Label("→", locale.Get("plain.fix")) + " " + locale.Get("doctor.fixHint", f.fixTitle)))
+	}
+}
+
+// RunDoctor checks the environment igitt runs in. With fix, the warnings and
+// problems that have an automatic fix are repaired right away.
+func RunDoctor(fix bool) error {
+	checks := []func() []finding{
+		checkGit,
+		checkIdentity,
+		checkConfig,
+		checkLogFile,
+		checkAlias,
+		checkTerminal,
+	}
+
+	problemCount := 0
+	warningCount := 0
+
+	for _, check := range checks {
+		for _, f := range check() {
+			printFinding(f, fix)
+
+			if fix && f.isFixable() {
+				logger.InfoLogger.Println("Doctor fixing:", f.title, f.fixTitle)
+				printDetail(color.CyanString(f.fixTitle))
+
+				err := f.fix()
+				if err == nil {
+					continue
+				}
+
+				logger.ErrorLogger.Println("Doctor fix failed:", f.title, err)
+				printDetail(color.HiRedString(locale.Get("doctor.fixFailed", err)))
+			}
+
+			switch f.status {
+			case statusProblem:
+				problemCount++
+			case statusWarning:
+				warningCount++
+			}
+		}
+	}
+
+	fmt.Println()
+
+	if problemCount > 0 {
+		return errors.New(locale.Get("doctor.problems", problemCount, warningCount))
+	}
+
+	if warningCount > 0 {
+		fmt.Println(color.HiYellowString(locale.Get("doctor.warnings", warningCount)))
+		return nil
+	}
+
+	fmt.Println(color.HiGreenString(locale.Get("doctor.ok")))
+	return nil
+}
@@ -2,32 +2,41 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
-	"time"
+	"strings"
 
-	"github.com/briandowns/spinner"
 	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/journal"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
 )
 
-func AddEverything() {
-	AddChanges([]string{"."})
+func AddEverything() error {
+	return AddChanges([]string{"."})
 }
 
-func AddChanges(arguments []string) {
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", append([]string{"add"}, arguments...)...).CombinedOutput()
-	progressIndicator.Stop()
+func AddChanges(arguments []string) error {
+	if DryRun {
+		printDryRun(append([]string{"add"}, arguments...), getAddEffect(arguments))
+		return nil
+	}
+
+	before := journal.CaptureState()
+
+	explainCommand("op-add", append([]string{"add"}, arguments...))
+
+	byteOut, errOut := runGitCommand(append([]string{"add"}, arguments...)...)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error adding changes:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error adding changes:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
 	}
-	logger.InfoLogger.Println("Adding changes:", errOut, string(byteOut))
 
-	fmt.Println("Changes added to the staging area.")
+	journal.Record(journal.OpAdd, locale.Get("journal.add", strings.Join(arguments, " ")), "", before)
+
+	logger.InfoLogger.Println("Adding changes:", errOut, byteOut)
+
+	fmt.Println(locale.Get("add.done"))
 
-	Status()
+	return Status()
 }
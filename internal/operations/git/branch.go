@@ -1,14 +1,14 @@
 package git
 
 import (
+	"errors"
 	"fmt"
-	"os/exec"
 	"strings"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
 	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/journal"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
 )
 
@@ -20,12 +20,9 @@ type BranchResult struct {
 func GetBranches() BranchResult {
 	var branches []string
 
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "branch", "-l").CombinedOutput()
-	progressIndicator.Stop()
+	byteOut, errOut := runGitCommand("branch", "-l")
 
-	branchesAsString := string(byteOut)
+	branchesAsString := byteOut
 	branchesAsString = utilities.RemoveLastEmptyLine(branchesAsString)
 
 	branches = strings.Split(branchesAsString, "\n")
@@ -35,12 +32,12 @@ func GetBranches() BranchResult {
 	branchesTrimmed := trimBranchPrefixes(branches)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
+		logger.ErrorLogger.Println("Error:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
 		return BranchResult{}
 	}
 
-	logger.InfoLogger.Println("Branch:", errOut, string(byteOut))
+	logger.InfoLogger.Println("Branch:", errOut, byteOut)
 
 	return BranchResult{
 		Branches:         branchesTrimmed,
@@ -72,101 +69,175 @@ func trimBranchPrefixes(branches []string) []string {
 	return trimmedBranches
 }
 
-func DoCustomBranchAction(arguments string) {
+func DoCustomBranchAction(arguments string) error {
 
 	if arguments == "" {
-		progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-		progressIndicator.Start()
-		byteOut, errOut := exec.Command("git", "branch").CombinedOutput()
-		progressIndicator.Stop()
+		explainCommand("op-branches", []string{"branch"})
+
+		byteOut, errOut := runGitCommand("branch")
 
 		if errOut != nil {
-			logger.ErrorLogger.Println("Error running branch command:", errOut, string(byteOut))
-			utilities.PrintGitError(string(byteOut))
-			return
+			logger.ErrorLogger.Println("Error running branch command:", errOut, byteOut)
+			utilities.PrintGitError(byteOut)
+			return errOut
 		}
 
-		fmt.Printf("%s", string(byteOut))
+		fmt.Printf("%s", byteOut)
+
+		logger.InfoLogger.Println("Branch command issued:", errOut, byteOut)
+		return nil
+	}
 
-		logger.InfoLogger.Println("Branch command issued:", errOut, string(byteOut))
-		return
+	fmt.Println(locale.Get("branch.custom", color.HiGreenString(arguments)))
+	if DryRun {
+		printDryRun([]string{"branch", arguments})
+		return nil
 	}
 
-	fmt.Println("Custom branch action:", color.HiGreenString(arguments))
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "branch", arguments).CombinedOutput()
-	progressIndicator.Stop()
+	explainCommand("op-branches", []string{"branch", arguments})
+
+	byteOut, errOut := runGitCommand("branch", arguments)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error running branch command:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error running branch command:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
 	}
 
-	logger.InfoLogger.Println("Branch command issued:", errOut, string(byteOut))
+	logger.InfoLogger.Println("Branch command issued:", errOut, byteOut)
+
+	return nil
 }
 
-func CheckoutBranch(branch string) {
-	fmt.Println("Checking out branch:", color.HiGreenString(branch))
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "checkout", branch).CombinedOutput()
-	progressIndicator.Stop()
+func CheckoutBranch(branch string) error {
+	if DryRun {
+		printDryRun([]string{"checkout", branch}, getCheckoutEffect(branch))
+		return nil
+	}
+
+	fmt.Println(locale.Get("branch.checkout", color.HiGreenString(branch)))
+	before := journal.CaptureState()
+
+	explainCommand("op-checkout", []string{"checkout", branch})
+
+	byteOut, errOut := runGitCommand("checkout", branch)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error checking out:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error checking out:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
 	}
 
-	logger.InfoLogger.Println("Checkout:", errOut, string(byteOut))
+	journal.Record(journal.OpCheckout, locale.Get("journal.checkout", branch), "", before)
+
+	logger.InfoLogger.Println("Checkout:", errOut, byteOut)
+
+	return nil
 }
 
-func CreateBranch(branch string) {
-	fmt.Println("Creating branch:", color.HiGreenString(branch))
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "checkout", "-b", branch).CombinedOutput()
-	progressIndicator.Stop()
+func CreateBranch(branch string) error {
+	if DryRun {
+		printDryRun([]string{"checkout", "-b", branch}, getCreateBranchEffect(branch))
+		return nil
+	}
+
+	fmt.Println(locale.Get("branch.create", color.HiGreenString(branch)))
+	before := journal.CaptureState()
+
+	explainCommand("op-create-branch", []string{"checkout", "-b", branch})
+
+	byteOut, errOut := runGitCommand("checkout", "-b", branch)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error creating branch:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error creating branch:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
 	}
 
-	logger.InfoLogger.Println("Branch created:", errOut, string(byteOut))
+	journal.Record(journal.OpCreateBranch, locale.Get("journal.createBranch", branch), "", before)
+
+	logger.InfoLogger.Println("Branch created:", errOut, byteOut)
+
+	return nil
 }
 
-func DeleteBranch(branch string) {
-	fmt.Println("Deleting branch:", color.HiRedString(branch))
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "branch", "-D", branch).CombinedOutput()
-	progressIndicator.Stop()
+func DeleteBranch(branch string) error {
+	if DryRun {
+		printDryRun([]string{"branch", "-D", branch}, getDeleteBranchEffect(branch))
+		return nil
+	}
+
+	fmt.Println(locale.Get("branch.delete", color.HiRedString(branch)))
+	before := journal.CaptureState()
+
+	explainCommand("op-delete-branch", []string{"branch", "-D", branch})
+
+	byteOut, errOut := runGitCommand("branch", "-D", branch)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error deleting branch:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error deleting branch:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
 	}
 
-	logger.InfoLogger.Println("Branch deleted:", errOut, string(byteOut))
+	journal.Record(journal.OpDeleteBranch, locale.Get("journal.deleteBranchOp", branch), "", before)
+
+	logger.InfoLogger.Println("Branch deleted:", errOut, byteOut)
+
+	return nil
 }
 
-func RenameBranch(oldBranch string, newBranch string) {
-	fmt.Println("Renaming branch:", color.HiGreenString(oldBranch), "to", color.HiGreenString(newBranch))
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "branch", "-m", oldBranch, newBranch).CombinedOutput()
-	progressIndicator.Stop()
+func RenameBranch(oldBranch string, newBranch string) error {
+	if DryRun {
+		printDryRun([]string{"branch", "-m", oldBranch, newBranch}, locale.Get("branch.wouldRename", oldBranch, newBranch))
+		return nil
+	}
+
+	fmt.Println(locale.Get("branch.rename", color.HiGreenString(oldBranch), color.HiGreenString(newBranch)))
+	before := journal.CaptureState()
+
+	explainCommand("op-rename-branch", []string{"branch", "-m", oldBranch, newBranch})
+
+	byteOut, errOut := runGitCommand("branch", "-m", oldBranch, newBranch)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error renaming branch:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error renaming branch:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+
+	journal.Record(journal.OpRenameBranch, locale.Get("journal.renameBranch", oldBranch, newBranch), "", before)
+
+	logger.InfoLogger.Println("Branch renamed:", errOut, byteOut)
+
+	return nil
+}
+
+// ConfirmDeleteBranch asks before deleting a branch, like the branch menu of
+// the interactive mode, and refuses to delete the checked out one.
+func ConfirmDeleteBranch(branch string) error {
+	if branch == GetBranches().CheckedOutBranch {
+		fmt.Println(locale.Get("branch.cannotDeleteCurrent"))
+		return &RefusedError{Reason: "the branch " + branch + " is checked out"}
+	}
+
+	if DryRun {
+		return DeleteBranch(branch)
+	}
+
+	confirmed, err := utilities.Confirm(locale.Get("menu.deleteBranch.title"), "\n  "+locale.Get("menu.deleteBranch.description", branch)+"\n")
+	if errors.Is(err, utilities.ErrNoTerminal) {
+		fmt.Println(color.HiRedString(locale.Get("branch.notDeleted", branch)))
+		return &RefusedError{Reason: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		fmt.Println(locale.Get("branch.kept"))
+		return &RefusedError{Reason: "deleting the branch " + branch + " was not confirmed"}
 	}
 
-	logger.InfoLogger.Println("Branch renamed:", errOut, string(byteOut))
+	return DeleteBranch(branch)
 }
@@ -2,27 +2,36 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
 )
 
-func CloneRepository(repoUrl string) {
-	fmt.Println("Cloning repository from " + repoUrl)
+func CloneRepository(repoUrl string, recurseSubmodules bool) error {
+	arguments := []string{"clone", repoUrl}
+	if recurseSubmodules {
+		arguments = []string{"clone", "--recurse-submodules", repoUrl}
+	}
+
+	if DryRun {
+		printDryRun(arguments, getCloneEffect(repoUrl))
+		return nil
+	}
 
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "clone", repoUrl).CombinedOutput()
-	progressIndicator.Stop()
+	fmt.Println(locale.Get("clone.start", repoUrl))
+
+	explainCommand("op-clone", arguments)
+
+	byteOut, errOut := runGitCommand(arguments...)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error cloning:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error cloning:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
 	}
 
-	logger.InfoLogger.Println("Cloning:", errOut, string(byteOut))
+	logger.InfoLogger.Println("Cloning:", errOut, byteOut)
+
+	return nil
 }
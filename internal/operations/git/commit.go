@@ -2,25 +2,69 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/journal"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
 )
 
-func CommitChanges(message string) {
-	fmt.Println("Committing changes")
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "commit", "-m", message).CombinedOutput()
-	progressIndicator.Stop()
+func CommitChanges(message string) error {
+	if DryRun {
+		printDryRun([]string{"commit", "-m", message}, getCommitEffect())
+		return nil
+	}
+
+	fmt.Println(locale.Get("commit.start"))
+	before := journal.CaptureState()
+
+	explainCommand("op-commit", []string{"commit", "-m", message})
+
+	byteOut, errOut := runGitCommand("commit", "-m", message)
 
 	if errOut != nil {
-		utilities.PrintGitError(string(byteOut))
-		logger.ErrorLogger.Fatal("Error committing changes:", errOut, string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error committing changes:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
 	}
-	logger.InfoLogger.Println("Committing changes:", errOut, string(byteOut))
+
+	journal.Record(journal.OpCommit, locale.Get("journal.commit", message), "", before)
+
+	logger.InfoLogger.Println("Committing changes:", errOut, byteOut)
+
+	return nil
+}
+
+// CommitOptions are the choices of the commit flow of the interactive mode.
+type CommitOptions struct {
+	// Paths are staged before committing
+	Paths []string
+	// All stages every change before committing
+	All bool
+	// Sync pulls and pushes after committing
+	Sync bool
+}
+
+// CommitWithOptions runs the whole commit flow of the interactive mode, it
+// stops at the first step that fails.
+func CommitWithOptions(message string, options CommitOptions) error {
+	if options.All {
+		if err := AddEverything(); err != nil {
+			return err
+		}
+	} else if len(options.Paths) > 0 {
+		if err := AddChanges(options.Paths); err != nil {
+			return err
+		}
+	}
+
+	if err := CommitChanges(message); err != nil {
+		return err
+	}
+
+	if options.Sync {
+		return SyncWithRemote()
+	}
+
+	return nil
 }
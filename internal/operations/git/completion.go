@@ -0,0 +1,124 @@
+package git
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// The completion helpers run while the shell waits for suggestions, they
+// must not print anything but the suggestions themselves, so they check for
+// a repository quietly before using the regular functions.
+
+func isInsideRepo() bool {
+	inside, ok := readGit("rev-parse", "--is-inside-work-tree")
+	return ok && inside == "true"
+}
+
+// CompleteBranches returns the local branches and the remote branches that
+// git checkout turns into a local branch of the same name, each with a short
+// description in cobra's "value\tdescription" format.
+func CompleteBranches() []string {
+	if !isInsideRepo() {
+		return nil
+	}
+
+	branchResult := GetBranches()
+
+	var completions []string
+	for _, branch := range branchResult.Branches {
+		if branch == "" || branch == branchResult.CheckedOutBranch {
+			continue
+		}
+		completions = append(completions, branch+"\tlocal branch")
+	}
+
+	remoteBranches, ok := readGit("for-each-ref", "--format=%(refname:short)", "refs/remotes")
+	if !ok || remoteBranches == "" {
+		return completions
+	}
+
+	for _, remoteBranch := range strings.Split(remoteBranches, "\n") {
+		_, name, found := strings.Cut(remoteBranch, "/")
+		if !found || name == "HEAD" || slices.Contains(branchResult.Branches, name) {
+			continue
+		}
+		completions = append(completions, name+"\tremote branch "+remoteBranch)
+	}
+
+	return completions
+}
+
+// CompleteModifiedFiles returns the changed and untracked paths that are not
+// in arguments yet, relative to the current directory like the shell expects
+// them.
+func CompleteModifiedFiles(arguments []string) []string {
+	if !isInsideRepo() {
+		return nil
+	}
+
+	// git status lists the paths relative to the root of the repository
+	prefix, ok := readGit("rev-parse", "--show-prefix")
+	if !ok {
+		return nil
+	}
+
+	modifications, err := GetModifications()
+	if err != nil {
+		return nil
+	}
+
+	statusTitles := make(map[string]string)
+	for _, status := range FileStatuses {
+		statusTitles[status.StatusLetter] = status.StatusTitle
+	}
+
+	var completions []string
+	for _, modification := range modifications {
+		// renames are listed as "old -> new", only the new path can be added
+		fileName := modification.FileName
+		if _, newName, renamed := strings.Cut(fileName, " -> "); renamed {
+			fileName = newName
+		}
+
+		relativeName, err := filepath.Rel(filepath.FromSlash(prefix), filepath.FromSlash(fileName))
+		if err != nil {
+			continue
+		}
+		// untracked directories keep their slash, so the shell can go on
+		// completing inside them
+		if strings.HasSuffix(fileName, "/") {
+			relativeName += string(filepath.Separator)
+		}
+		fileName = relativeName
+
+		if slices.Contains(arguments, fileName) {
+			continue
+		}
+		completions = append(completions, fileName+"\t"+statusTitles[modification.StatusLetter])
+	}
+
+	return completions
+}
+
+func CompleteRemotes() []string {
+	if !isInsideRepo() {
+		return nil
+	}
+
+	remotes, ok := readGit("remote", "-v")
+	if !ok || remotes == "" {
+		return nil
+	}
+
+	var completions []string
+	for _, line := range strings.Split(remotes, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[2] != "(push)" {
+			continue
+		}
+		completions = append(completions, fields[0]+"\t"+fields[1])
+	}
+
+	return completions
+}
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+)
+
+// ShellStepError is returned when a shell step of a custom command failed.
+// It does not unwrap, a missing shell must not be reported as missing git.
+type ShellStepError struct {
+	Line     string
+	ExitCode int
+	Err      error
+}
+
+func (e *ShellStepError) Error() string {
+	if e.ExitCode == -1 {
+		return fmt.Sprintf("the step %q could not be run: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("the step %q exited with code %d", e.Line, e.ExitCode)
+}
+
+func getShellCommand(line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", line)
+	}
+	return exec.Command("sh", "-c", line)
+}
+
+// RunCustomGitStep runs one git step of a user-defined command and shows
+// git's output, as the user wrote the command and expects to see it.
+func RunCustomGitStep(arguments []string) error {
+	if DryRun {
+		printDryRun(arguments)
+		return nil
+	}
+
+	printExplanation(arguments, "")
+
+	byteOut, errOut := runGitCommand(arguments...)
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error running custom step:", FormatGitCommand(arguments), errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+	logger.InfoLogger.Println("Ran custom step:", FormatGitCommand(arguments), byteOut)
+
+	if output := strings.TrimSpace(byteOut); output != "" {
+		fmt.Println(output)
+	}
+
+	return nil
+}
+
+// RunCustomShellStep runs a shell step of a user-defined command in the
+// terminal, with the prompt values added to its environment.
+func RunCustomShellStep(line string, environment []string) error {
+	if DryRun {
+		logger.InfoLogger.Println("Dry run, not executing:", line)
+		fmt.Println(color.HiBlackString("[dry-run]"), color.CyanString(line))
+		return nil
+	}
+
+	if Explain {
+		fmt.Println(color.HiBlackString("$"), color.CyanString(line))
+	}
+
+	command := getShellCommand(line)
+	command.Env = append(os.Environ(), environment...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	if err := command.Run(); err != nil {
+		exitCode := -1
+
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			exitCode = exitError.ExitCode()
+		}
+
+		logger.ErrorLogger.Println("Error running custom shell step:", line, err)
+		return &ShellStepError{Line: line, ExitCode: exitCode, Err: err}
+	}
+	logger.InfoLogger.Println("Ran custom shell step:", line)
+
+	return nil
+}
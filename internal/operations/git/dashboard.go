@@ -0,0 +1,111 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RepoSummary is what the dashboard of the interactive mode shows about the
+// current repository.
+type RepoSummary struct {
+	Name       string
+	Root       string
+	Branch     string
+	Upstream   string
+	Ahead      int
+	Behind     int
+	Staged     int
+	Unstaged   int
+	Untracked  int
+	Conflicted int
+	Stashes    int
+	LastCommit string
+}
+
+// GetRepoSummary collects the summary with a few read-only git commands that
+// run at the same time. Parts git cannot tell, e.g. the last commit of a new
+// repository, stay empty. It returns false outside a repository.
+func GetRepoSummary() (RepoSummary, bool) {
+	var summary RepoSummary
+	var root, status, stashes, lastCommit string
+	var rootOk, statusOk, stashesOk bool
+
+	var wait sync.WaitGroup
+	wait.Add(4)
+
+	go func() {
+		defer wait.Done()
+		root, rootOk = readGit("rev-parse", "--show-toplevel")
+	}()
+	go func() {
+		defer wait.Done()
+		status, statusOk = readGit("status", "--porcelain=v2", "--branch")
+	}()
+	go func() {
+		defer wait.Done()
+		stashes, stashesOk = readGit("stash", "list")
+	}()
+	go func() {
+		defer wait.Done()
+		lastCommit, _ = readGit("log", "-1", "--format=%s")
+	}()
+
+	wait.Wait()
+
+	if !rootOk || !statusOk {
+		return summary, false
+	}
+
+	summary.Root = root
+	summary.Name = filepath.Base(root)
+	summary.LastCommit = lastCommit
+
+	if stashesOk {
+		summary.Stashes = countLines(stashes)
+	}
+
+	parseStatusV2(status, &summary)
+
+	return summary, true
+}
+
+// parseStatusV2 reads the branch headers and entries of
+// git status --porcelain=v2 --branch.
+func parseStatusV2(status string, summary *RepoSummary) {
+	for _, line := range strings.Split(status, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "#":
+			if len(fields) < 3 {
+				continue
+			}
+			switch fields[1] {
+			case "branch.head":
+				summary.Branch = fields[2]
+			case "branch.upstream":
+				summary.Upstream = fields[2]
+			case "branch.ab":
+				summary.Ahead = toInt(strings.TrimPrefix(fields[2], "+"))
+				if len(fields) > 3 {
+					summary.Behind = toInt(strings.TrimPrefix(fields[3], "-"))
+				}
+			}
+		case "1", "2":
+			if fields[1][0] != '.' {
+				summary.Staged++
+			}
+			if len(fields[1]) > 1 && fields[1][1] != '.' {
+				summary.Unstaged++
+			}
+		case "u":
+			summary.Conflicted++
+		case "?":
+			summary.Untracked++
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+)
+
+// DryRun makes every mutating operation print the git command it would run,
+// and its expected effect, instead of running it.
+var DryRun bool
+
+func FormatGitCommand(arguments []string) string {
+	quoted := make([]string, len(arguments))
+
+	for i, argument := range arguments {
+		if argument == "" || strings.ContainsAny(argument, " \t\n\"'$`\\*?[]{}()<>|&;#") {
+			quoted[i] = "'" + strings.ReplaceAll(argument, "'", `'\''`) + "'"
+			continue
+		}
+		quoted[i] = argument
+	}
+
+	return "git " + strings.Join(quoted, " ")
+}
+
+func printDryRun(arguments []string, effects ...string) {
+	logger.InfoLogger.Println("Dry run, not executing:", FormatGitCommand(arguments))
+
+	fmt.Println(color.HiBlackString("[dry-run]"), color.CyanString(FormatGitCommand(arguments)))
+
+	for _, effect := range effects {
+		if effect == "" {
+			continue
+		}
+		for _, line := range strings.Split(effect, "\n") {
+			fmt.Println(color.HiBlackString("          "), line)
+		}
+	}
+}
+
+// readGit runs a read-only git command, e.g. to compute the expected effect
+// of a dry run. Failures only mean that the information is not available.
+func readGit(arguments ...string) (string, bool) {
+	byteOut, errOut := exec.Command("git", arguments...).Output()
+	if errOut != nil {
+		logger.InfoLogger.Println("Could not read from git:", FormatGitCommand(arguments), errOut)
+		return "", false
+	}
+
+	return strings.TrimSpace(string(byteOut)), true
+}
+
+func toInt(text string) int {
+	number, err := strconv.Atoi(text)
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+func countLines(text string) int {
+	if text == "" {
+		return 0
+	}
+	return len(strings.Split(text, "\n"))
+}
+
+func getAddEffect(arguments []string) string {
+	output, ok := readGit(append([]string{"add", "--dry-run"}, arguments...)...)
+	if !ok {
+		return ""
+	}
+
+	if output == "" {
+		return locale.Get("dryRun.addNothing")
+	}
+
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		files = append(files, "  "+strings.Trim(strings.TrimPrefix(line, "add "), "'"))
+	}
+
+	return locale.Plural(len(files), "dryRun.add") + "\n" + strings.Join(files, "\n")
+}
+
+func getCommitEffect() string {
+	staged, ok := readGit("diff", "--cached", "--name-only")
+	if !ok {
+		return ""
+	}
+
+	if staged == "" {
+		return locale.Get("dryRun.commitNothing")
+	}
+
+	branch, _ := readGit("symbolic-ref", "--quiet", "--short", "HEAD")
+	if branch == "" {
+		branch = locale.Get("dryRun.detachedHead")
+	}
+
+	return locale.Plural(countLines(staged), "dryRun.commit", branch)
+}
+
+func getCheckoutEffect(branch string) string {
+	current, _ := readGit("symbolic-ref", "--quiet", "--short", "HEAD")
+
+	if _, exists := readGit("rev-parse", "--verify", "--quiet", "refs/heads/"+branch); exists {
+		return locale.Get("dryRun.checkout", current, branch)
+	}
+
+	if _, exists := readGit("rev-parse", "--verify", "--quiet", branch); exists {
+		return locale.Get("dryRun.checkout", current, branch)
+	}
+
+	return locale.Get("dryRun.checkoutMissing", branch)
+}
+
+func getCreateBranchEffect(branch string) string {
+	if _, exists := readGit("rev-parse", "--verify", "--quiet", "refs/heads/"+branch); exists {
+		return locale.Get("dryRun.createBranchExists", branch)
+	}
+
+	head, ok := readGit("rev-parse", "--short", "HEAD")
+	if !ok {
+		return locale.Get("dryRun.createBranch", branch)
+	}
+
+	return locale.Get("dryRun.createBranchAt", branch, head)
+}
+
+func getDeleteBranchEffect(branch string) string {
+	commit, ok := readGit("rev-parse", "--short", "refs/heads/"+branch)
+	if !ok {
+		return locale.Get("dryRun.deleteBranchMissing", branch)
+	}
+
+	effect := locale.Get("dryRun.deleteBranch", branch, commit)
+
+	if unmerged, ok := readGit("rev-list", "--count", "HEAD.."+branch); ok && unmerged != "0" {
+		effect += "\n" + locale.Plural(toInt(unmerged), "dryRun.deleteBranchUnmerged")
+	}
+
+	return effect
+}
+
+func getPullEffect() string {
+	upstream, ok := readGit("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	if !ok {
+		return locale.Get("dryRun.pullNoUpstream")
+	}
+
+	incoming, ok := readGit("rev-list", "--count", "HEAD..@{u}")
+	if !ok {
+		return ""
+	}
+
+	return locale.Plural(toInt(incoming), "dryRun.pull", upstream)
+}
+
+func getPushEffect() string {
+	branch, _ := readGit("symbolic-ref", "--quiet", "--short", "HEAD")
+
+	upstream, ok := readGit("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	if !ok {
+		count, _ := readGit("rev-list", "--count", "HEAD")
+		return locale.Plural(toInt(count), "dryRun.pushNew", branch)
+	}
+
+	outgoing, ok := readGit("rev-list", "--count", "@{u}..HEAD")
+	if !ok {
+		return ""
+	}
+
+	return locale.Plural(toInt(outgoing), "dryRun.push", upstream)
+}
+
+func getResetEffect(target string, mode string) string {
+	from, okFrom := readGit("rev-parse", "--short", "HEAD")
+	to, okTo := readGit("rev-parse", "--short", target)
+	if !okFrom || !okTo {
+		return ""
+	}
+
+	effect := locale.Get("dryRun.reset", from, to)
+
+	if dropped, ok := readGit("rev-list", "--count", target+"..HEAD"); ok && dropped != "0" {
+		effect += "\n" + locale.Plural(toInt(dropped), "dryRun.resetDropped")
+	}
+
+	switch mode {
+	case "soft":
+		effect += "\n" + locale.Get("dryRun.resetSoft")
+	case "mixed":
+		effect += "\n" + locale.Get("dryRun.resetMixed")
+	case "hard":
+		if changed, ok := readGit("diff", "HEAD", "--name-only"); ok && changed != "" {
+			effect += "\n" + locale.Plural(countLines(changed), "dryRun.resetHard")
+		}
+	}
+
+	return effect
+}
+
+func getStashEffect() string {
+	changed, ok := readGit("diff", "HEAD", "--name-only")
+	if !ok {
+		return ""
+	}
+	if changed == "" {
+		return locale.Get("dryRun.stashNothing")
+	}
+	return locale.Plural(countLines(changed), "dryRun.stash")
+}
+
+func getStashPopEffect() string {
+	top, ok := readGit("stash", "list", "-1")
+	if !ok || top == "" {
+		return locale.Get("dryRun.stashPopEmpty")
+	}
+	return locale.Get("dryRun.stashPop", top)
+}
+
+func getInitEffect(directory string) string {
+	if utilities.CheckIsRepo() {
+		return locale.Get("dryRun.initExisting", directory)
+	}
+	return locale.Get("dryRun.init", directory)
+}
+
+func getCloneEffect(repoUrl string) string {
+	directory := strings.TrimSuffix(strings.TrimSuffix(repoUrl, "/"), ".git")
+	directory = directory[strings.LastIndexAny(directory, "/:")+1:]
+
+	return locale.Get("dryRun.clone", directory)
+}
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/nstr-dev/igitt/internal/utilities/giterrors"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+)
+
+// GitError is returned when git itself failed. It keeps git's exit code and
+// stderr so callers can decide what to do and scripts get a useful status.
+type GitError struct {
+	Arguments []string
+	ExitCode  int
+	Stderr    string
+	Err       error
+}
+
+func (e *GitError) Error() string {
+	message := strings.TrimSpace(e.Stderr)
+	if message == "" {
+		message = e.Err.Error()
+	}
+	if line, _, found := strings.Cut(message, "\n"); found {
+		message = line
+	}
+
+	return fmt.Sprintf("%s exited with code %d: %s", FormatGitCommand(e.Arguments), e.ExitCode, message)
+}
+
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
+
+func (e *GitError) IsNotARepository() bool {
+	return strings.Contains(e.Stderr, "not a git repository")
+}
+
+// RefusedError is returned when igitt did not run an operation on purpose,
+// e.g. because it would lose uncommitted work.
+type RefusedError struct {
+	Reason string
+}
+
+func (e *RefusedError) Error() string {
+	return e.Reason
+}
+
+var ErrNothingToUndo = &RefusedError{Reason: "there is nothing to undo"}
+
+func newGitError(arguments []string, stderr string, err error) *GitError {
+	exitCode := -1
+
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		exitCode = exitError.ExitCode()
+	}
+
+	return &GitError{
+		Arguments: arguments,
+		ExitCode:  exitCode,
+		Stderr:    stderr,
+		Err:       err,
+	}
+}
+
+// runGitCommand runs git behind a spinner and returns its combined output,
+// which is what the user should see. On failure the error is a *GitError.
+func runGitCommand(arguments ...string) (string, error) {
+	var combined, stderr bytes.Buffer
+
+	command := giterrors.Command(arguments...)
+	command.Stdout = &combined
+	command.Stderr = io.MultiWriter(&combined, &stderr)
+
+	logger.DebugLogger.Println("Running", FormatGitCommand(arguments))
+
+	// a spinner would only litter captured output and screen readers
+	var err error
+	if plain.Enabled || !plain.IsTerminalOutput() {
+		err = command.Run()
+	} else {
+		progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		progressIndicator.Start()
+		err = command.Run()
+		progressIndicator.Stop()
+	}
+
+	if err != nil {
+		return combined.String(), newGitError(arguments, stderr.String(), err)
+	}
+
+	return combined.String(), nil
+}
@@ -0,0 +1,40 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+)
+
+// Explain makes every operation print the git command it runs together with
+// a short explanation, so users learn the commands behind igitt.
+var Explain bool
+
+// GetExplanation returns the explanation of an operation in the current
+// language, falling back to English. It is empty for operations without
+// one.
+func GetExplanation(operationId string) string {
+	key := "explain." + operationId
+	if operationId == "" || !locale.Has(key) {
+		return ""
+	}
+
+	return locale.Get(key)
+}
+
+func explainCommand(operationId string, arguments []string) {
+	printExplanation(arguments, GetExplanation(operationId))
+}
+
+func printExplanation(arguments []string, explanation string) {
+	if !Explain {
+		return
+	}
+
+	fmt.Println(color.HiBlackString("$"), color.CyanString(FormatGitCommand(arguments)))
+
+	if explanation != "" {
+		fmt.Println(color.HiBlackString("  " + explanation))
+	}
+}
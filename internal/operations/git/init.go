@@ -3,30 +3,36 @@ package git
 import (
 	"fmt"
 	"os"
-	"os/exec"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
 )
 
-func InitRepository() {
+func InitRepository() error {
 	mydir, err := os.Getwd()
 	if err != nil {
 		logger.ErrorLogger.Println(err)
+		return err
 	}
-	fmt.Println("Initializing repository in " + mydir)
 
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "init").CombinedOutput()
-	progressIndicator.Stop()
+	if DryRun {
+		printDryRun([]string{"init"}, getInitEffect(mydir))
+		return nil
+	}
+
+	fmt.Println(locale.Get("init.start", mydir))
+
+	explainCommand("op-init", []string{"init"})
+
+	byteOut, errOut := runGitCommand("init")
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error initializing Git repository:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error initializing Git repository:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
 	}
-	logger.InfoLogger.Println("Initializing Git repository:", errOut, string(byteOut))
+	logger.InfoLogger.Println("Initializing Git repository:", errOut, byteOut)
+
+	return nil
 }
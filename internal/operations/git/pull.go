@@ -2,25 +2,40 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/journal"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
 )
 
-func PullRemote() {
-	fmt.Println("Pulling from remote repository")
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "pull").CombinedOutput()
-	progressIndicator.Stop()
+func PullRemote(recurseSubmodules bool) error {
+	arguments := []string{"pull"}
+	if recurseSubmodules {
+		arguments = append(arguments, "--recurse-submodules")
+	}
+
+	if DryRun {
+		printDryRun(arguments, getPullEffect())
+		return nil
+	}
+
+	fmt.Println(locale.Get("pull.start"))
+	before := journal.CaptureState()
+
+	explainCommand("op-pull", arguments)
+
+	byteOut, errOut := runGitCommand(arguments...)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error pulling from remote repository:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error pulling from remote repository:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
 	}
-	logger.InfoLogger.Println("Pulling from remote repository:", errOut, string(byteOut))
+
+	journal.Record(journal.OpPull, locale.Get("journal.pull"), "", before)
+
+	logger.InfoLogger.Println("Pulling from remote repository:", errOut, byteOut)
+
+	return nil
 }
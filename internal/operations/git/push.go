@@ -2,25 +2,53 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
 )
 
-func PushRemote() {
-	fmt.Println("Pushing to remote repository")
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "-c", "push.autoSetupRemote=true", "push").CombinedOutput()
-	progressIndicator.Stop()
+func PushRemote() error {
+	return PushToRemote("")
+}
+
+// PushToRemote pushes the current branch to the given remote, or to its
+// upstream if remote is empty.
+func PushToRemote(remote string) error {
+	arguments := []string{"-c", "push.autoSetupRemote=true", "push"}
+	if remote != "" {
+		arguments = append(arguments, remote)
+	}
+
+	if DryRun {
+		printDryRun(arguments, getPushEffect())
+		return nil
+	}
+
+	if remote != "" {
+		fmt.Println(locale.Get("push.startRemote", remote))
+	} else {
+		fmt.Println(locale.Get("push.start"))
+	}
+	explainCommand("op-push", arguments)
+
+	byteOut, errOut := runGitCommand(arguments...)
 
 	if errOut != nil {
-		logger.ErrorLogger.Println("Error pushing to remote repository:", errOut, string(byteOut))
-		utilities.PrintGitError(string(byteOut))
-		return
+		logger.ErrorLogger.Println("Error pushing to remote repository:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+	logger.InfoLogger.Println("Pushing to remote repository:", errOut, byteOut)
+
+	return nil
+}
+
+// SyncWithRemote pulls, including the submodules if there are any, and then
+// pushes the current branch.
+func SyncWithRemote() error {
+	if err := PullRemote(HasSubmodules()); err != nil {
+		return err
 	}
-	logger.InfoLogger.Println("Pushing to remote repository:", errOut, string(byteOut))
+	return PushRemote()
 }
@@ -0,0 +1,40 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/journal"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+)
+
+var ResetModes = []string{"soft", "mixed", "hard"}
+
+func ResetToCommit(target string, mode string) error {
+	if DryRun {
+		printDryRun([]string{"reset", "--" + mode, target}, getResetEffect(target, mode))
+		return nil
+	}
+
+	fmt.Println(locale.Get("reset.start", color.HiYellowString(target), mode))
+
+	before := journal.CaptureState()
+
+	explainCommand("op-reset", []string{"reset", "--" + mode, target})
+
+	byteOut, errOut := runGitCommand("reset", "--"+mode, target)
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error resetting:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+
+	journal.Record(journal.OpReset, locale.Get("journal.reset", mode, target), mode, before)
+
+	logger.InfoLogger.Println("Resetting:", errOut, byteOut)
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/journal"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+)
+
+func StashChanges(message string) error {
+	arguments := []string{"stash", "push"}
+	if message != "" {
+		arguments = append(arguments, "-m", message)
+	}
+
+	if DryRun {
+		printDryRun(arguments, getStashEffect())
+		return nil
+	}
+
+	fmt.Println(locale.Get("stash.start"))
+
+	before := journal.CaptureState()
+
+	explainCommand("op-stash", arguments)
+
+	byteOut, errOut := runGitCommand(arguments...)
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error stashing changes:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+
+	journal.Record(journal.OpStash, locale.Get("journal.stash"), message, before)
+
+	logger.InfoLogger.Println("Stashing changes:", errOut, byteOut)
+
+	return nil
+}
+
+func PopStash() error {
+	if DryRun {
+		printDryRun([]string{"stash", "pop"}, getStashPopEffect())
+		return nil
+	}
+
+	fmt.Println(locale.Get("stash.pop"))
+
+	stashMessage, _ := exec.Command("git", "log", "-1", "--format=%gs", "-g", "refs/stash").Output()
+
+	before := journal.CaptureState()
+
+	explainCommand("op-stash-pop", []string{"stash", "pop"})
+
+	byteOut, errOut := runGitCommand("stash", "pop")
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error popping stash:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+
+	journal.Record(journal.OpStashPop, locale.Get("journal.stashPop"), utilities.RemoveLastEmptyLine(string(stashMessage)), before)
+
+	logger.InfoLogger.Println("Popping stash:", errOut, byteOut)
+
+	return nil
+}
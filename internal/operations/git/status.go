@@ -2,15 +2,16 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
 	"sort"
 	"strings"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
 	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
+	"github.com/rivo/uniseg"
 )
 
 type FileStatus struct {
@@ -73,20 +74,48 @@ var FileStatuses = []ModifiedStatusInfo{
 	{color.FgHiBlack, "Ignored", "!!"},
 }
 
-func Status() {
+// getStatusTitle translates the title of a status, the catalog keys use the
+// two letters of git status --short with spaces written as dashes.
+func getStatusTitle(status ModifiedStatusInfo) string {
+	key := "status." + strings.ReplaceAll(status.StatusLetter, " ", "-")
+	if title, found := locale.Lookup(key); found {
+		return title
+	}
+	return status.StatusTitle
+}
+
+// GetStatusLabel returns the translated title for the two letters of git
+// status --short, or the letters if git reported an unknown status.
+func GetStatusLabel(statusLetter string) string {
+	for _, status := range FileStatuses {
+		if status.StatusLetter == statusLetter {
+			return getStatusTitle(status)
+		}
+	}
+	return statusLetter
+}
+
+// printHeading underlines the title of a listing.
+func printHeading(title string) {
+	fmt.Printf("\n%s:\n%s\n\n", title, strings.Repeat("=", uniseg.StringWidth(title)+1))
+}
+
+func Status() error {
+	explainCommand("op-status", []string{"status", "--porcelain"})
+
 	modifications, err := GetModifications()
 	if err != nil {
 		logger.ErrorLogger.Println("Failed to get modifications: ", err)
-		return
+		return err
 	}
 
 	if len(modifications) == 0 {
-		fmt.Println(color.HiGreenString("✓"), "Up to date.")
-		return
+		fmt.Println(color.HiGreenString(plain.Label("✓", locale.Get("plain.ok"))), locale.Get("status.upToDate"))
+		printChangedSubmodules()
+		return nil
 	}
 
-	fmt.Printf("\nFiles with changes:\n")
-	fmt.Printf("===================\n\n")
+	printHeading(locale.Get("status.heading"))
 
 	statusMap := make(map[string]ModifiedStatusInfo)
 	for _, status := range FileStatuses {
@@ -97,22 +126,25 @@ func Status() {
 
 	for _, modification := range modifications {
 		if status, exists := statusMap[modification.StatusLetter]; exists {
-			color := color.New(status.StatusColor).SprintFunc()
-			coloredTitle := color(status.StatusTitle)
-			if len(coloredTitle) > maxWidth {
-				maxWidth = len(coloredTitle) + 4
-			}
+			maxWidth = max(maxWidth, uniseg.StringWidth(getStatusTitle(status))+4)
 		}
 	}
 
 	for _, modification := range modifications {
 		if status, exists := statusMap[modification.StatusLetter]; exists {
-			color := color.New(status.StatusColor).SprintFunc()
-			coloredTitle := color(status.StatusTitle)
-			format := fmt.Sprintf("%%-%ds%%s\n", maxWidth)
-			fmt.Printf(format, coloredTitle, modification.FileName)
+			title := getStatusTitle(status)
+			if plain.Enabled {
+				fmt.Printf("%s: %s\n", title, modification.FileName)
+				continue
+			}
+			paddedTitle := title + strings.Repeat(" ", maxWidth-uniseg.StringWidth(title))
+			fmt.Printf("%s%s\n", theme.Status(status.StatusLetter, status.StatusColor, paddedTitle), modification.FileName)
 		}
 	}
+
+	printChangedSubmodules()
+
+	return nil
 }
 
 func GetModifications() ([]FileStatus, error) {
@@ -214,12 +246,9 @@ func GetStagedModificationCountAsString() string {
 }
 
 func runGitStatus() (string, error) {
-	progressIndicator := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	progressIndicator.Start()
-	byteOut, errOut := exec.Command("git", "status", "--porcelain").CombinedOutput()
-	progressIndicator.Stop()
+	byteOut, errOut := runGitCommand("status", "--porcelain")
 
-	logger.InfoLogger.Println("Fetching git status:", errOut, string(byteOut))
+	logger.InfoLogger.Println("Fetching git status:", errOut, byteOut)
 
-	return string(byteOut), errOut
+	return byteOut, errOut
 }
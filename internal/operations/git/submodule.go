@@ -0,0 +1,303 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/rivo/uniseg"
+)
+
+type Submodule struct {
+	Path             string
+	RecordedCommit   string
+	CheckedOutCommit string
+	Initialized      bool
+	NewCommits       bool
+	Conflicted       bool
+	Dirty            bool
+}
+
+// https://git-scm.com/docs/git-submodule#_commands
+
+func HasSubmodules() bool {
+	repoRoot := utilities.GetRepoRoot()
+	if repoRoot == "" {
+		return false
+	}
+
+	info, err := os.Stat(filepath.Join(repoRoot, ".gitmodules"))
+	return err == nil && info.Size() > 0
+}
+
+func GetSubmodules() ([]Submodule, error) {
+	byteOut, errOut := runGitCommand("submodule", "status")
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error getting submodule status:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return nil, errOut
+	}
+
+	cachedByteOut, cachedErrOut := runGitCommand("submodule", "status", "--cached")
+
+	if cachedErrOut != nil {
+		logger.ErrorLogger.Println("Error getting recorded submodule commits:", cachedErrOut, cachedByteOut)
+		utilities.PrintGitError(cachedByteOut)
+		return nil, cachedErrOut
+	}
+
+	logger.InfoLogger.Println("Submodule status:", errOut, byteOut)
+
+	recordedCommits := make(map[string]string)
+	for _, line := range parseSubmoduleStatus(cachedByteOut) {
+		recordedCommits[line.path] = line.commit
+	}
+
+	var submodules []Submodule
+	repoRoot := utilities.GetRepoRoot()
+
+	for _, line := range parseSubmoduleStatus(byteOut) {
+		submodule := Submodule{
+			Path:             line.path,
+			RecordedCommit:   recordedCommits[line.path],
+			CheckedOutCommit: line.commit,
+			Initialized:      line.prefix != '-',
+			NewCommits:       line.prefix == '+',
+			Conflicted:       line.prefix == 'U',
+		}
+
+		if !submodule.Initialized {
+			submodule.CheckedOutCommit = ""
+		}
+
+		if submodule.Initialized {
+			dirty, err := IsWorktreeDirty(filepath.Join(repoRoot, submodule.Path))
+			if err != nil {
+				logger.WarningLogger.Println("Could not determine submodule state:", submodule.Path, err)
+			}
+			submodule.Dirty = dirty
+		}
+
+		submodules = append(submodules, submodule)
+	}
+
+	return submodules, nil
+}
+
+type submoduleStatusLine struct {
+	prefix byte
+	commit string
+	path   string
+}
+
+func parseSubmoduleStatus(output string) []submoduleStatusLine {
+	var lines []submoduleStatusLine
+
+	for _, line := range strings.Split(utilities.RemoveLastEmptyLine(output), "\n") {
+		if len(line) < 2 {
+			continue
+		}
+
+		fields := strings.Fields(line[1:])
+		if len(fields) < 2 {
+			continue
+		}
+
+		lines = append(lines, submoduleStatusLine{prefix: line[0], commit: fields[0], path: fields[1]})
+	}
+
+	return lines
+}
+
+func GetSubmoduleStateLabel(submodule Submodule) string {
+	if !submodule.Initialized {
+		return "not initialized"
+	}
+
+	var states []string
+
+	if submodule.Conflicted {
+		states = append(states, "merge conflict")
+	}
+	if submodule.NewCommits {
+		states = append(states, "new commits")
+	}
+	if submodule.Dirty {
+		states = append(states, "modified content")
+	}
+
+	if len(states) == 0 {
+		return "up to date"
+	}
+
+	return strings.Join(states, ", ")
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	if commit == "" {
+		return "-"
+	}
+	return commit
+}
+
+func ListSubmodules() error {
+	explainCommand("op-submodule-status", []string{"submodule", "status"})
+
+	submodules, err := GetSubmodules()
+	if err != nil {
+		return err
+	}
+
+	if len(submodules) == 0 {
+		fmt.Println(locale.Get("submodule.none"))
+		return nil
+	}
+
+	printHeading(locale.Get("submodule.heading"))
+
+	columns := []string{locale.Get("submodule.path"), locale.Get("submodule.recorded"), locale.Get("submodule.checkedOut"), locale.Get("submodule.state")}
+
+	maxPathWidth := uniseg.StringWidth(columns[0])
+	for _, submodule := range submodules {
+		maxPathWidth = max(maxPathWidth, len(submodule.Path))
+	}
+
+	recordedWidth := max(9, uniseg.StringWidth(columns[1]))
+	checkedOutWidth := max(11, uniseg.StringWidth(columns[2]))
+
+	format := fmt.Sprintf("%%-%ds  %%-%ds  %%-%ds  %%s\n", maxPathWidth, recordedWidth, checkedOutWidth)
+	fmt.Printf(format, columns[0], columns[1], columns[2], columns[3])
+
+	for _, submodule := range submodules {
+		fmt.Printf(format,
+			submodule.Path,
+			shortCommit(submodule.RecordedCommit),
+			shortCommit(submodule.CheckedOutCommit),
+			colorSubmoduleState(submodule),
+		)
+	}
+
+	return nil
+}
+
+func colorSubmoduleState(submodule Submodule) string {
+	state := GetSubmoduleStateLabel(submodule)
+
+	if submodule.Conflicted {
+		return color.HiRedString(state)
+	}
+	if !submodule.Initialized {
+		return color.HiBlackString(state)
+	}
+	if submodule.NewCommits || submodule.Dirty {
+		return color.HiYellowString(state)
+	}
+	return color.HiGreenString(state)
+}
+
+func printChangedSubmodules() {
+	if !HasSubmodules() {
+		return
+	}
+
+	submodules, err := GetSubmodules()
+	if err != nil {
+		return
+	}
+
+	var changed []Submodule
+	for _, submodule := range submodules {
+		if submodule.NewCommits || submodule.Dirty || submodule.Conflicted {
+			changed = append(changed, submodule)
+		}
+	}
+
+	if len(changed) == 0 {
+		return
+	}
+
+	printHeading(locale.Get("submodule.changedHeading"))
+
+	maxPathWidth := 0
+	for _, submodule := range changed {
+		maxPathWidth = max(maxPathWidth, len(submodule.Path))
+	}
+
+	format := fmt.Sprintf("%%-%ds  %%s\n", maxPathWidth)
+
+	for _, submodule := range changed {
+		fmt.Printf(format, submodule.Path, colorSubmoduleState(submodule))
+	}
+}
+
+func runSubmoduleCommand(description string, message string, arguments ...string) error {
+	if DryRun {
+		printDryRun(append([]string{"submodule"}, arguments...))
+		return nil
+	}
+
+	fmt.Println(message)
+
+	explainCommand("op-submodule-"+description, append([]string{"submodule"}, arguments...))
+
+	byteOut, errOut := runGitCommand(append([]string{"submodule"}, arguments...)...)
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error running submodule "+description+":", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+
+	fmt.Printf("%s", byteOut)
+
+	logger.InfoLogger.Println("Submodule "+description+":", errOut, byteOut)
+
+	return nil
+}
+
+func InitSubmodules(paths []string) error {
+	return runSubmoduleCommand("init", locale.Get("submodule.init"), append([]string{"init", "--"}, paths...)...)
+}
+
+func UpdateSubmodules(paths []string, remote bool) error {
+	arguments := []string{"update", "--init", "--recursive"}
+	message := locale.Get("submodule.update")
+
+	if remote {
+		arguments = append(arguments, "--remote")
+		message = locale.Get("submodule.updateRemote")
+	}
+
+	return runSubmoduleCommand("update", message, append(arguments, append([]string{"--"}, paths...)...)...)
+}
+
+func SyncSubmodules(paths []string) error {
+	return runSubmoduleCommand("sync", locale.Get("submodule.sync"), append([]string{"sync", "--recursive", "--"}, paths...)...)
+}
+
+func AddSubmodule(repoUrl string, path string) error {
+	arguments := []string{"add", "--", repoUrl}
+	if path != "" {
+		arguments = append(arguments, path)
+	}
+
+	return runSubmoduleCommand("add", locale.Get("submodule.add", color.HiGreenString(repoUrl)), arguments...)
+}
+
+func DeinitSubmodule(path string, force bool) error {
+	arguments := []string{"deinit"}
+	if force {
+		arguments = append(arguments, "--force")
+	}
+
+	return runSubmoduleCommand("deinit", locale.Get("submodule.deinit", color.HiRedString(path)), append(arguments, "--", path)...)
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/giterrors"
+	"github.com/nstr-dev/igitt/internal/utilities/journal"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
+)
+
+func init() {
+	giterrors.RunCommand = RunSuggestedFix
+}
+
+// suggestedFixOperations maps the git commands of the suggested fixes to the
+// operation they are explained and journaled as. Commands that only read are
+// not listed.
+var suggestedFixOperations = map[string]struct {
+	operationId string
+	journalOp   string
+}{
+	"add":      {"op-add", journal.OpAdd},
+	"checkout": {"op-checkout", journal.OpCheckout},
+	"pull":     {"op-pull", journal.OpPull},
+	"push":     {"op-push", ""},
+	"stash":    {"op-stash", journal.OpStash},
+}
+
+// RunSuggestedFix runs the git command of a suggestion for a recognized
+// error. It does not offer suggestions again when it fails, a fix must not
+// lead into a loop of fixes.
+func RunSuggestedFix(arguments []string) error {
+	if DryRun {
+		printDryRun(arguments)
+		return nil
+	}
+
+	operation := suggestedFixOperations[arguments[0]]
+
+	before := journal.CaptureState()
+
+	if Explain {
+		explainCommand(operation.operationId, arguments)
+	} else {
+		fmt.Println(color.CyanString(FormatGitCommand(arguments)))
+	}
+
+	byteOut, errOut := runGitCommand(arguments...)
+
+	if output := strings.TrimSpace(byteOut); output != "" {
+		if errOut != nil {
+			fmt.Println(theme.ErrorMessage(output))
+		} else {
+			fmt.Println(output)
+		}
+	}
+
+	if errOut != nil {
+		return errOut
+	}
+
+	if operation.journalOp != "" {
+		journal.Record(operation.journalOp, locale.Get("journal.suggestedFix", FormatGitCommand(arguments)), "", before)
+	}
+
+	logger.InfoLogger.Println("Ran suggested fix:", FormatGitCommand(arguments))
+
+	return nil
+}
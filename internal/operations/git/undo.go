@@ -0,0 +1,159 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/journal"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+)
+
+func GetUndoDescription() string {
+	entry, err := journal.GetLastUndoable()
+	if err != nil {
+		return locale.Get("undo.nothing")
+	}
+
+	description := locale.Get("undo.description", entry.Description, entry.Time.Format(time.DateTime)) + "\n"
+
+	if err := journal.CheckUndoable(entry); err != nil {
+		return description + "\n  " + getNotUndoableReason(err)
+	}
+
+	steps := journal.PlanUndo(entry)
+	if len(steps) == 0 {
+		return description + "\n  " + locale.Get("undo.noSteps")
+	}
+
+	for _, step := range steps {
+		description += "\n  - " + step.Description
+	}
+
+	return description
+}
+
+// getNotUndoableReason explains why journal.CheckUndoable refused an entry.
+func getNotUndoableReason(err error) string {
+	if errors.Is(err, journal.ErrUntrackedFiles) {
+		return locale.Get("undo.untrackedFiles")
+	}
+	return locale.Get("undo.indexNotRecorded")
+}
+
+func Undo() error {
+	entry, err := journal.GetLastUndoable()
+	if errors.Is(err, journal.ErrNothingToUndo) {
+		logger.InfoLogger.Println("Nothing to undo:", err)
+		fmt.Println(locale.Get("undo.nothing"))
+		return ErrNothingToUndo
+	}
+	if err != nil {
+		logger.ErrorLogger.Println("Failed to read journal:", err)
+		utilities.PrintGeneralError(locale.Get("undo.journalFailed"))
+		return err
+	}
+
+	changes := journal.GetChangesSince(entry)
+	if len(changes) > 0 {
+		logger.WarningLogger.Println("Refusing to undo, repository changed:", changes)
+		utilities.PrintGeneralError(locale.Get("undo.changed", entry.Description, strings.Join(changes, "\n  - ")))
+		return &RefusedError{Reason: "the repository changed since the last operation"}
+	}
+
+	if err := journal.CheckUndoable(entry); err != nil {
+		logger.WarningLogger.Println("Refusing to undo:", err)
+		utilities.PrintGeneralError(locale.Get("undo.cannotUndo", entry.Description, getNotUndoableReason(err)))
+		return &RefusedError{Reason: err.Error()}
+	}
+
+	if DryRun {
+		fmt.Println(locale.Get("undo.wouldUndo", color.HiYellowString(entry.Description)))
+		for _, step := range journal.PlanUndo(entry) {
+			printDryRun(step.Arguments, step.Description)
+		}
+		return nil
+	}
+
+	fmt.Println(locale.Get("undo.start", color.HiYellowString(entry.Description)))
+
+	for _, step := range journal.PlanUndo(entry) {
+		fmt.Println("  " + step.Description)
+
+		printExplanation(step.Arguments, "")
+
+		byteOut, errOut := runGitCommand(step.Arguments...)
+
+		if errOut != nil {
+			logger.ErrorLogger.Println("Error undoing operation:", errOut, byteOut)
+			utilities.PrintGitError(byteOut)
+			return errOut
+		}
+
+		logger.InfoLogger.Println("Undo step:", strings.Join(step.Arguments, " "), byteOut)
+	}
+
+	if err := journal.MarkUndone(entry.Id); err != nil {
+		logger.ErrorLogger.Println("Failed to mark journal entry as undone:", err)
+	}
+
+	fmt.Println(color.HiGreenString(plain.Label("✓", locale.Get("plain.ok"))), locale.Get("undo.done"))
+
+	return nil
+}
+
+func ListUndo() error {
+	entries, err := journal.ReadEntries()
+	if err != nil {
+		logger.ErrorLogger.Println("Failed to read journal:", err)
+		utilities.PrintGeneralError(locale.Get("undo.journalFailed"))
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println(locale.Get("undo.noHistory"))
+		return nil
+	}
+
+	printHeading(locale.Get("undo.historyHeading"))
+
+	nextUndo := true
+
+	for i := len(entries) - 1; i >= 0 && i >= len(entries)-10; i-- {
+		entry := entries[i]
+
+		title := fmt.Sprintf("%s  %s", entry.Time.Format(time.DateTime), entry.Description)
+
+		if entry.Undone {
+			fmt.Println(color.HiBlackString(title + "  " + locale.Get("undo.undone")))
+			continue
+		}
+
+		if nextUndo {
+			fmt.Println(color.HiYellowString(title) + color.HiBlackString("  <- igitt undo"))
+			nextUndo = false
+		} else {
+			fmt.Println(title)
+		}
+
+		if err := journal.CheckUndoable(entry); err != nil {
+			fmt.Println("    " + getNotUndoableReason(err))
+			continue
+		}
+
+		steps := journal.PlanUndo(entry)
+		if len(steps) == 0 {
+			fmt.Println("    " + locale.Get("undo.noSteps"))
+		}
+		for _, step := range steps {
+			fmt.Println("    " + step.Description)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,499 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/giterrors"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
+	"github.com/rivo/uniseg"
+)
+
+// WorkspaceRepository is a repository igitt workspace runs in, Name is how
+// the table shows it.
+type WorkspaceRepository struct {
+	Name string
+	Path string
+}
+
+// WorkspaceResult is one row of the workspace table. Output is only set by
+// exec, Err by whatever failed in the repository and Skipped by pull for
+// branches without an upstream.
+type WorkspaceResult struct {
+	Repository WorkspaceRepository
+	Summary    RepoSummary
+	Output     string
+	Err        error
+	Skipped    bool
+}
+
+// DiscoverRepositories finds the repositories below root. It does not look
+// inside repositories, hidden directories or node_modules, so submodules
+// and dependencies are left out.
+func DiscoverRepositories(root string) ([]WorkspaceRepository, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var repositories []WorkspaceRepository
+
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			logger.WarningLogger.Println("Skipping while searching for repositories:", err)
+			return fs.SkipDir
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		if path != root && (strings.HasPrefix(entry.Name(), ".") || entry.Name() == "node_modules") {
+			return fs.SkipDir
+		}
+
+		if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+			return nil
+		}
+
+		name, err := filepath.Rel(root, path)
+		if err != nil || name == "." {
+			name = filepath.Base(path)
+		}
+
+		repositories = append(repositories, WorkspaceRepository{Name: filepath.ToSlash(name), Path: path})
+		return fs.SkipDir
+	})
+
+	return repositories, err
+}
+
+// GetWorkspaceRepositories names the saved repositories by their path, with
+// the home directory shortened to ~.
+func GetWorkspaceRepositories(paths []string) []WorkspaceRepository {
+	home, _ := os.UserHomeDir()
+
+	repositories := make([]WorkspaceRepository, len(paths))
+
+	for i, path := range paths {
+		name := path
+		if home != "" && strings.HasPrefix(path, home+string(filepath.Separator)) {
+			name = "~" + strings.TrimPrefix(path, home)
+		}
+		repositories[i] = WorkspaceRepository{Name: filepath.ToSlash(name), Path: path}
+	}
+
+	return repositories
+}
+
+// GetRepositoryRoot returns the root of the repository path is in.
+func GetRepositoryRoot(path string) (string, bool) {
+	return readGit("-C", path, "rev-parse", "--show-toplevel")
+}
+
+// runWorkspaceGit runs git in a repository of the workspace. Several run at
+// the same time, so there is no spinner and git must not ask for
+// credentials on the terminal.
+func runWorkspaceGit(repository WorkspaceRepository, arguments ...string) (string, error) {
+	arguments = append([]string{"-C", repository.Path}, arguments...)
+
+	var stdout, stderr bytes.Buffer
+
+	command := giterrors.Command(arguments...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	command.Env = append(command.Env, "GIT_TERMINAL_PROMPT=0")
+
+	logger.DebugLogger.Println("Running", FormatGitCommand(arguments))
+
+	if err := command.Run(); err != nil {
+		logger.ErrorLogger.Println("Failed in workspace repository:", repository.Path, err, stderr.String())
+		return stdout.String(), newGitError(arguments, stderr.String(), err)
+	}
+
+	return stdout.String(), nil
+}
+
+func getWorkspaceStatus(repository WorkspaceRepository) WorkspaceResult {
+	result := WorkspaceResult{Repository: repository}
+
+	status, err := runWorkspaceGit(repository, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	parseStatusV2(status, &result.Summary)
+	return result
+}
+
+// workspaceProgress counts the finished repositories on one line while the
+// others still run, only in a terminal.
+type workspaceProgress struct {
+	mutex    sync.Mutex
+	finished int
+	total    int
+	shown    bool
+}
+
+func (p *workspaceProgress) step() {
+	if !p.shown {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.finished++
+	fmt.Print("\r" + color.HiBlackString(locale.Get("workspace.progress", p.finished, p.total)))
+}
+
+func (p *workspaceProgress) clear() {
+	if p.shown {
+		fmt.Print("\r\033[K")
+	}
+}
+
+// runInWorkspace runs work for every repository, at most jobs at the same
+// time. The results keep the order of the repositories.
+func runInWorkspace(repositories []WorkspaceRepository, jobs int, work func(WorkspaceRepository) WorkspaceResult) []WorkspaceResult {
+	results := make([]WorkspaceResult, len(repositories))
+	slots := make(chan struct{}, max(1, jobs))
+
+	progress := &workspaceProgress{total: len(repositories), shown: !plain.Enabled && plain.IsTerminalOutput()}
+
+	var wait sync.WaitGroup
+
+	for i, repository := range repositories {
+		wait.Add(1)
+		slots <- struct{}{}
+
+		go func() {
+			defer wait.Done()
+			results[i] = work(repository)
+			<-slots
+			progress.step()
+		}()
+	}
+
+	wait.Wait()
+	progress.clear()
+
+	return results
+}
+
+// WorkspaceStatus shows the branch, changes and ahead and behind counts of
+// every repository.
+func WorkspaceStatus(repositories []WorkspaceRepository, jobs int) error {
+	explainCommand("op-workspace-status", []string{"status", "--porcelain=v2", "--branch"})
+
+	results := runInWorkspace(repositories, jobs, getWorkspaceStatus)
+	printWorkspaceResults(results)
+
+	return getWorkspaceError(results)
+}
+
+// WorkspaceFetch fetches every repository from its remote.
+func WorkspaceFetch(repositories []WorkspaceRepository, jobs int) error {
+	return runWorkspaceOperation("op-workspace-fetch", repositories, jobs, []string{"fetch", "--prune"}, false)
+}
+
+// WorkspacePull pulls every repository. Only fast-forwards are made, a
+// repository that needs a merge is reported instead of starting one in
+// dozens of repositories at once. Branches without an upstream are skipped.
+func WorkspacePull(repositories []WorkspaceRepository, jobs int) error {
+	return runWorkspaceOperation("op-workspace-pull", repositories, jobs, []string{"pull", "--ff-only"}, true)
+}
+
+func runWorkspaceOperation(operationId string, repositories []WorkspaceRepository, jobs int, arguments []string, needsUpstream bool) error {
+	if DryRun {
+		for _, repository := range repositories {
+			printDryRun(append([]string{"-C", repository.Path}, arguments...))
+		}
+		return nil
+	}
+
+	explainCommand(operationId, arguments)
+
+	results := runInWorkspace(repositories, jobs, func(repository WorkspaceRepository) WorkspaceResult {
+		if needsUpstream {
+			result := getWorkspaceStatus(repository)
+			if result.Err == nil && result.Summary.Upstream == "" {
+				result.Skipped = true
+				return result
+			}
+		}
+
+		_, err := runWorkspaceGit(repository, arguments...)
+
+		// the status is still worth showing when the operation failed
+		result := getWorkspaceStatus(repository)
+		if err != nil {
+			result.Err = err
+		}
+		return result
+	})
+
+	printWorkspaceResults(results)
+
+	return getWorkspaceError(results)
+}
+
+// WorkspaceExec runs a command in every repository and prints its output
+// grouped by repository above the table.
+func WorkspaceExec(repositories []WorkspaceRepository, jobs int, command []string) error {
+	if DryRun {
+		for _, repository := range repositories {
+			fmt.Println(color.HiBlackString("[dry-run]"), color.CyanString(strings.Join(command, " ")), color.HiBlackString(locale.Get("workspace.in", repository.Path)))
+		}
+		return nil
+	}
+
+	// a command without a path is the same in every repository, a missing
+	// one is reported once instead of in every row
+	if filepath.Base(command[0]) == command[0] {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			// not wrapped, igitt would take it for a missing git
+			return fmt.Errorf("%s was not found on your PATH", command[0])
+		}
+	}
+
+	results := runInWorkspace(repositories, jobs, func(repository WorkspaceRepository) WorkspaceResult {
+		process := exec.Command(command[0], command[1:]...)
+		process.Dir = repository.Path
+		process.Env = append(os.Environ(), "IGITT_REPO_ROOT="+repository.Path)
+
+		logger.DebugLogger.Println("Running in", repository.Path+":", strings.Join(command, " "))
+
+		output, err := process.CombinedOutput()
+
+		result := getWorkspaceStatus(repository)
+		result.Output = string(output)
+
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			result.Err = errors.New(locale.Get("workspace.exitCode", exitError.ExitCode()))
+		} else if err != nil {
+			result.Err = err
+		}
+
+		return result
+	})
+
+	for _, result := range results {
+		if strings.TrimSpace(result.Output) == "" {
+			continue
+		}
+		printHeading(result.Repository.Name)
+		fmt.Println(strings.TrimRight(result.Output, "\n"))
+	}
+	fmt.Println()
+
+	printWorkspaceResults(results)
+
+	return getWorkspaceError(results)
+}
+
+// getWorkspaceError sums up the failed repositories. Git errors end igitt
+// with the git exit code and are not printed again, so the sum is printed
+// here.
+func getWorkspaceError(results []WorkspaceResult) error {
+	var failed []WorkspaceResult
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	summary := locale.Get("workspace.failed", len(failed), len(results))
+
+	var gitError *GitError
+	if errors.As(failed[0].Err, &gitError) {
+		fmt.Println("\n" + theme.ErrorTitle(summary))
+		return fmt.Errorf("%s: %w", summary, gitError)
+	}
+
+	return errors.New(summary)
+}
+
+// getWorkspaceMessage shortens an error to fit a table cell. The command is
+// the same in every row, so only git's own message is kept, preferably the
+// fatal or error line after its hints.
+func getWorkspaceMessage(err error) string {
+	var gitError *GitError
+	if !errors.As(err, &gitError) {
+		return err.Error()
+	}
+
+	message := ""
+	for _, line := range strings.Split(gitError.Stderr, "\n") {
+		line = strings.TrimSpace(line)
+
+		if reason, found := strings.CutPrefix(line, "fatal: "); found {
+			return reason
+		}
+		if reason, found := strings.CutPrefix(line, "error: "); found {
+			return reason
+		}
+		if message == "" && line != "" && !strings.HasPrefix(line, "hint:") {
+			message = line
+		}
+	}
+
+	if message == "" {
+		return locale.Get("workspace.exitCode", gitError.ExitCode)
+	}
+	return message
+}
+
+type workspaceCell struct {
+	text  string
+	color *color.Color
+}
+
+func getWorkspaceRow(result WorkspaceResult) []workspaceCell {
+	summary := result.Summary
+
+	getCount := func(count int, attribute color.Attribute) workspaceCell {
+		if count == 0 {
+			return workspaceCell{text: "0"}
+		}
+		return workspaceCell{strconv.Itoa(count), color.New(attribute)}
+	}
+
+	branch := summary.Branch
+	if branch == "(detached)" {
+		branch = locale.Get("dashboard.detached")
+	}
+
+	ahead, behind := workspaceCell{text: "-"}, workspaceCell{text: "-"}
+	if summary.Upstream != "" {
+		ahead = getCount(summary.Ahead, color.FgHiYellow)
+		behind = getCount(summary.Behind, color.FgHiYellow)
+	}
+
+	outcome := workspaceCell{locale.Get("workspace.ok"), color.New(color.FgHiGreen)}
+	if result.Skipped {
+		outcome = workspaceCell{locale.Get("workspace.skipped"), color.New(color.FgHiBlack)}
+	}
+	if result.Err != nil {
+		outcome = workspaceCell{getWorkspaceMessage(result.Err), color.New(color.FgHiRed)}
+	}
+
+	return []workspaceCell{
+		{result.Repository.Name, color.New(color.FgBlue)},
+		{branch, color.New(color.FgCyan)},
+		getCount(summary.Staged, color.FgHiGreen),
+		getCount(summary.Unstaged, color.FgHiYellow),
+		getCount(summary.Untracked, color.FgHiBlack),
+		ahead,
+		behind,
+		outcome,
+	}
+}
+
+// printWorkspaceResults lines up the results in columns. The widths are
+// taken before the cells are colored.
+func printWorkspaceResults(results []WorkspaceResult) {
+	if plain.Enabled {
+		printPlainWorkspaceResults(results)
+		return
+	}
+
+	bold := color.New(color.Bold)
+
+	rows := [][]workspaceCell{{
+		{locale.Get("workspace.repository"), bold},
+		{locale.Get("workspace.branch"), bold},
+		{locale.Get("workspace.staged"), bold},
+		{locale.Get("workspace.unstaged"), bold},
+		{locale.Get("workspace.untracked"), bold},
+		{locale.Get("workspace.ahead"), bold},
+		{locale.Get("workspace.behind"), bold},
+		{locale.Get("workspace.result"), bold},
+	}}
+	for _, result := range results {
+		rows = append(rows, getWorkspaceRow(result))
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], uniseg.StringWidth(cell.text))
+		}
+	}
+
+	for _, row := range rows {
+		var line strings.Builder
+
+		for i, cell := range row {
+			text := cell.text
+			if i < len(row)-1 {
+				text += strings.Repeat(" ", widths[i]-uniseg.StringWidth(cell.text)+2)
+			}
+
+			if cell.color != nil {
+				text = cell.color.Sprint(text)
+			}
+			line.WriteString(text)
+		}
+
+		fmt.Println(line.String())
+	}
+}
+
+// printPlainWorkspaceResults writes a labeled line per repository, columns
+// are hard to follow for screen readers.
+func printPlainWorkspaceResults(results []WorkspaceResult) {
+	for _, result := range results {
+		summary := result.Summary
+
+		branch := summary.Branch
+		if branch == "(detached)" {
+			branch = locale.Get("dashboard.detached")
+		}
+
+		parts := []string{
+			locale.Get("dashboard.plain.branch", branch),
+			locale.Plural(summary.Staged, "dashboard.staged"),
+			locale.Plural(summary.Unstaged, "dashboard.unstaged"),
+			locale.Plural(summary.Untracked, "dashboard.untracked"),
+		}
+
+		if summary.Upstream != "" {
+			parts = append(parts, locale.Get("workspace.plain.aheadBehind", summary.Ahead, summary.Behind))
+		} else {
+			parts = append(parts, locale.Get("dashboard.noUpstream"))
+		}
+
+		switch {
+		case result.Err != nil:
+			parts = append(parts, locale.Get("plain.failed")+" "+getWorkspaceMessage(result.Err))
+		case result.Skipped:
+			parts = append(parts, locale.Get("workspace.skipped"))
+		default:
+			parts = append(parts, locale.Get("workspace.ok"))
+		}
+
+		fmt.Printf("%s: %s\n", result.Repository.Name, strings.Join(parts, ", "))
+	}
+}
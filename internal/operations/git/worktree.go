@@ -0,0 +1,271 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/giterrors"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+)
+
+type Worktree struct {
+	Path     string
+	Head     string
+	Branch   string
+	Bare     bool
+	Detached bool
+	Locked   bool
+	Prunable bool
+	Dirty    bool
+}
+
+// https://git-scm.com/docs/git-worktree#_porcelain_format
+
+func GetWorktrees() ([]Worktree, error) {
+	byteOut, errOut := runGitCommand("worktree", "list", "--porcelain")
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error listing worktrees:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return nil, errOut
+	}
+
+	logger.InfoLogger.Println("Listing worktrees:", errOut, byteOut)
+
+	worktrees := parseWorktreeList(byteOut)
+
+	for i := range worktrees {
+		if worktrees[i].Bare || worktrees[i].Prunable {
+			continue
+		}
+
+		dirty, err := IsWorktreeDirty(worktrees[i].Path)
+		if err != nil {
+			logger.WarningLogger.Println("Could not determine worktree state:", worktrees[i].Path, err)
+			continue
+		}
+		worktrees[i].Dirty = dirty
+	}
+
+	return worktrees, nil
+}
+
+func parseWorktreeList(output string) []Worktree {
+	var worktrees []Worktree
+
+	for _, line := range strings.Split(utilities.RemoveLastEmptyLine(output), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+
+		if key == "worktree" {
+			worktrees = append(worktrees, Worktree{Path: value})
+			continue
+		}
+
+		if len(worktrees) == 0 {
+			continue
+		}
+
+		current := &worktrees[len(worktrees)-1]
+
+		switch key {
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			current.Bare = true
+		case "detached":
+			current.Detached = true
+		case "locked":
+			current.Locked = true
+		case "prunable":
+			current.Prunable = true
+		}
+	}
+
+	return worktrees
+}
+
+func IsWorktreeDirty(path string) (bool, error) {
+	arguments := []string{"-C", path, "status", "--porcelain"}
+
+	byteOut, errOut := giterrors.Command(arguments...).CombinedOutput()
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error checking worktree state:", errOut, string(byteOut))
+		return false, newGitError(arguments, string(byteOut), errOut)
+	}
+
+	return strings.TrimSpace(string(byteOut)) != "", nil
+}
+
+func GetWorktreeBranchLabel(worktree Worktree) string {
+	if worktree.Bare {
+		return "(bare)"
+	}
+	if worktree.Detached {
+		if len(worktree.Head) > 7 {
+			return "(detached at " + worktree.Head[:7] + ")"
+		}
+		return "(detached)"
+	}
+	return worktree.Branch
+}
+
+func GetWorktreeStateLabel(worktree Worktree) string {
+	if worktree.Prunable {
+		return "prunable"
+	}
+	if worktree.Bare {
+		return ""
+	}
+	if worktree.Dirty {
+		return "dirty"
+	}
+	return "clean"
+}
+
+func ListWorktrees() error {
+	explainCommand("op-worktrees", []string{"worktree", "list", "--porcelain"})
+
+	worktrees, err := GetWorktrees()
+	if err != nil {
+		return err
+	}
+
+	printHeading(locale.Get("worktree.heading"))
+
+	maxPathWidth := 0
+	maxBranchWidth := 0
+
+	for _, worktree := range worktrees {
+		maxPathWidth = max(maxPathWidth, len(worktree.Path))
+		maxBranchWidth = max(maxBranchWidth, len(GetWorktreeBranchLabel(worktree)))
+	}
+
+	format := fmt.Sprintf("%%-%ds  %%-%ds  %%s\n", maxPathWidth, maxBranchWidth)
+
+	for _, worktree := range worktrees {
+		state := GetWorktreeStateLabel(worktree)
+
+		switch state {
+		case "dirty":
+			state = color.HiYellowString(state)
+		case "prunable":
+			state = color.HiRedString(state)
+		default:
+			state = color.HiGreenString(state)
+		}
+
+		if worktree.Locked {
+			state += color.HiBlackString(" " + locale.Get("worktree.locked"))
+		}
+
+		fmt.Printf(format, worktree.Path, GetWorktreeBranchLabel(worktree), state)
+	}
+
+	return nil
+}
+
+func AddWorktree(path string, branch string, createBranch bool) error {
+	arguments := []string{"worktree", "add", path, branch}
+	if createBranch {
+		arguments = []string{"worktree", "add", "-b", branch, path}
+	}
+
+	if DryRun {
+		if createBranch {
+			printDryRun(arguments, locale.Get("dryRun.worktreeAddNew", branch, path))
+		} else {
+			printDryRun(arguments, locale.Get("dryRun.worktreeAdd", branch, path))
+		}
+		return nil
+	}
+
+	if createBranch {
+		fmt.Println(locale.Get("worktree.createNew", color.HiGreenString(path), color.HiGreenString(branch)))
+	} else {
+		fmt.Println(locale.Get("worktree.create", color.HiGreenString(path), color.HiGreenString(branch)))
+	}
+
+	explainCommand("op-worktree-add", arguments)
+
+	byteOut, errOut := runGitCommand(arguments...)
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error creating worktree:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+
+	logger.InfoLogger.Println("Worktree created:", errOut, byteOut)
+
+	return nil
+}
+
+func RemoveWorktree(path string, force bool) error {
+	if !force {
+		dirty, err := IsWorktreeDirty(path)
+		if err == nil && dirty {
+			logger.WarningLogger.Println("Refusing to remove dirty worktree without confirmation:", path)
+			utilities.PrintGeneralError(locale.Get("worktree.dirty", path))
+			return &RefusedError{Reason: "the worktree at " + path + " has uncommitted changes"}
+		}
+	}
+
+	arguments := []string{"worktree", "remove", path}
+	if force {
+		arguments = append(arguments, "--force")
+	}
+
+	if DryRun {
+		printDryRun(arguments, locale.Get("dryRun.worktreeRemove", path))
+		return nil
+	}
+
+	fmt.Println(locale.Get("worktree.remove", color.HiRedString(path)))
+
+	explainCommand("op-worktree-remove", arguments)
+
+	byteOut, errOut := runGitCommand(arguments...)
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error removing worktree:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+
+	logger.InfoLogger.Println("Worktree removed:", errOut, byteOut)
+
+	return nil
+}
+
+func PruneWorktrees() error {
+	if DryRun {
+		stale, _ := readGit("worktree", "prune", "--dry-run", "-v")
+		if stale == "" {
+			stale = locale.Get("dryRun.worktreePruneNone")
+		}
+		printDryRun([]string{"worktree", "prune", "-v"}, stale)
+		return nil
+	}
+
+	fmt.Println(locale.Get("worktree.prune"))
+	explainCommand("op-worktree-prune", []string{"worktree", "prune", "-v"})
+
+	byteOut, errOut := runGitCommand("worktree", "prune", "-v")
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error pruning worktrees:", errOut, byteOut)
+		utilities.PrintGitError(byteOut)
+		return errOut
+	}
+
+	fmt.Printf("%s", byteOut)
+
+	logger.InfoLogger.Println("Worktrees pruned:", errOut, byteOut)
+
+	return nil
+}
@@ -1,63 +1,342 @@
 package operations
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
+	"strings"
 
 	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
 )
 
-func CreateAliasScripts() {
-	executable, err := os.Executable()
-	if err != nil {
-		logger.ErrorLogger.Println("Failed to get executable path:", err)
-		return
-	}
-	executablePath := filepath.Dir(executable)
-	logger.InfoLogger.Printf("Creating alias for executable in %v", executablePath)
+const aliasName = "igt"
+
+const (
+	AliasScript  = "script"
+	AliasSymlink = "symlink"
+)
+
+// Alias is an igt wrapper script or symlink that starts igitt.
+type Alias struct {
+	Path   string
+	Kind   string
+	Target string
+}
 
-	aliasScriptWindows := `@ECHO OFF
-if exist `+ executable +` (
-	`+ executable +` %*
+// IsStale tells whether the executable the alias starts no longer exists,
+// e.g. after igitt was moved or reinstalled somewhere else.
+func (a Alias) IsStale() bool {
+	info, err := os.Stat(a.Target)
+	return err != nil || info.IsDir()
+}
+
+const aliasScriptWindows = `@ECHO OFF
+if exist "%[1]s" (
+	"%[1]s" %%*
 ) else (
 	echo.
 	echo ================ igitt ================
 	echo.
-	echo Run following command to [32mcreate[0m an alias script [34m^(igitt =^> igt^)[0m:
+	echo Run following command to [32mcreate[0m an alias script [34m^(igitt =^> igt^)[0m:
 	echo.
-	echo [36migitt mkalias[0m
+	echo [36migitt alias install[0m
 	echo.
 	echo.
-	echo If you don't want this alias, run this to [31mremove[0m this script:
+	echo If you don't want this alias, run this to [31mremove[0m this script:
 	echo.
-	echo [36mdel %~dpnx0[0m
+	echo [36mdel %%~dpnx0[0m
 )`
 
-	aliasScriptUnix := `#!/bin/bash
-"` + executable + `" "$@"`
+const aliasScriptUnix = `#!/bin/sh
+# igitt alias, update it with: igitt alias install
+exec "%s" "$@"
+`
 
-	var aliasFileName, aliasScriptContent string
+// The scripts of older versions are recognized as well, so they can be
+// refreshed and removed.
+var aliasTargetPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?m)^exec "((?:[^"\\]|\\.)*)" "\$@"$`),
+	regexp.MustCompile(`(?m)^"((?:[^"\\]|\\.)*)" "\$@"$`),
+	regexp.MustCompile(`(?mi)^if exist "?([^"\r\n]+?)"? \($`),
+}
 
-	switch runtime.GOOS {
-	case "windows":
-		aliasFileName = filepath.Join(executablePath, "igt.cmd")
-		aliasScriptContent = aliasScriptWindows
-	case "linux", "darwin":
-		aliasFileName = filepath.Join(executablePath, "igt")
-		aliasScriptContent = aliasScriptUnix
-	default:
-		logger.ErrorLogger.Println("Unsupported OS")
-		return
+var shellEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+var shellUnescaper = regexp.MustCompile(`\\(.)`)
+
+func getAliasFileName() string {
+	if runtime.GOOS == "windows" {
+		return aliasName + ".cmd"
 	}
+	return aliasName
+}
 
-	if err := os.WriteFile(aliasFileName, []byte(aliasScriptContent), 0755); err != nil {
-		logger.ErrorLogger.Println("Failed to create alias script:", err)
-		return
+func getAliasScript(executable string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf(aliasScriptWindows, executable)
+	}
+	return fmt.Sprintf(aliasScriptUnix, shellEscaper.Replace(executable))
+}
+
+// GetAliasScriptPath returns where the igt script is put by default, next
+// to the igitt executable.
+func GetAliasScriptPath() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(executable), getAliasFileName()), nil
+}
+
+// readAlias recognizes the aliases igitt created, anything else named igt
+// is left alone.
+func readAlias(path string) (Alias, bool) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return Alias{}, false
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil || !strings.HasPrefix(strings.ToLower(filepath.Base(target)), "igitt") {
+			return Alias{}, false
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		return Alias{Path: path, Kind: AliasSymlink, Target: target}, true
+	}
+
+	if !info.Mode().IsRegular() || info.Size() > 4096 {
+		return Alias{}, false
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Alias{}, false
+	}
+
+	for _, pattern := range aliasTargetPatterns {
+		if match := pattern.FindStringSubmatch(string(content)); match != nil {
+			target := match[1]
+			if !strings.HasPrefix(strings.ToLower(match[0]), "if exist") {
+				target = shellUnescaper.ReplaceAllString(target, "$1")
+			}
+			return Alias{Path: path, Kind: AliasScript, Target: target}, true
+		}
+	}
+
+	return Alias{}, false
+}
+
+func getAliasDirectories() []string {
+	var directories []string
+
+	if executable, err := os.Executable(); err == nil {
+		directories = append(directories, filepath.Dir(executable))
+	}
+
+	for _, directory := range filepath.SplitList(os.Getenv("PATH")) {
+		if directory != "" && !slices.Contains(directories, directory) {
+			directories = append(directories, directory)
+		}
+	}
+
+	return directories
+}
+
+// FindAliases looks for igt aliases next to the executable and on the PATH.
+func FindAliases() []Alias {
+	var aliases []Alias
+
+	for _, directory := range getAliasDirectories() {
+		if alias, found := readAlias(filepath.Join(directory, getAliasFileName())); found {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return aliases
+}
+
+func isOnPath(directory string) bool {
+	for _, pathDirectory := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(pathDirectory) == filepath.Clean(directory) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeAlias(path string, kind string, executable string) error {
+	if info, err := os.Lstat(path); err == nil {
+		if _, ours := readAlias(path); !ours {
+			return fmt.Errorf("%s already exists and is not an igitt alias, remove it first", path)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	if kind == AliasSymlink {
+		return os.Symlink(executable, path)
+	}
+
+	return os.WriteFile(path, []byte(getAliasScript(executable)), 0755)
+}
+
+// InstallAlias creates the igt alias in directory, next to the executable
+// if it is empty, and points stale aliases elsewhere on the PATH at this
+// executable again.
+func InstallAlias(directory string, kind string) error {
+	if kind != AliasScript && kind != AliasSymlink {
+		return errors.New(locale.Get("alias.unknownType", kind, AliasScript, AliasSymlink))
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		logger.ErrorLogger.Println("Failed to get executable path:", err)
+		return err
+	}
+
+	if directory == "" {
+		directory = filepath.Dir(executable)
+	}
+
+	aliasPath := filepath.Join(directory, getAliasFileName())
+	logger.InfoLogger.Printf("Creating %s alias at %s for %s", kind, aliasPath, executable)
+
+	if err := writeAlias(aliasPath, kind, executable); err != nil {
+		logger.ErrorLogger.Println("Failed to create alias:", err)
+		return err
+	}
+
+	fmt.Println(locale.Get("alias.created", color.GreenString(aliasPath)))
+
+	for _, alias := range FindAliases() {
+		if alias.Path == aliasPath || !alias.IsStale() {
+			continue
+		}
+
+		if err := writeAlias(alias.Path, alias.Kind, executable); err != nil {
+			logger.ErrorLogger.Println("Failed to refresh stale alias:", alias.Path, err)
+			fmt.Println(color.HiYellowString(locale.Get("alias.updateFailed", alias.Path, err)))
+			continue
+		}
+
+		logger.InfoLogger.Println("Refreshed stale alias:", alias.Path)
+		fmt.Println(locale.Get("alias.updated", color.GreenString(alias.Path)))
+	}
+
+	if !isOnPath(directory) {
+		fmt.Println(color.HiYellowString("\n" + locale.Get("alias.notOnPath", directory)))
+	}
+
+	return nil
+}
+
+// CreateAliasScripts creates the igt script next to the executable.
+func CreateAliasScripts() error {
+	return InstallAlias("", AliasScript)
+}
+
+// UninstallAlias removes the igt aliases igitt created, next to the
+// executable, on the PATH and in directory.
+func UninstallAlias(directory string) error {
+	aliases := FindAliases()
+
+	if directory != "" {
+		aliasPath := filepath.Join(directory, getAliasFileName())
+		if alias, found := readAlias(aliasPath); found && !slices.ContainsFunc(aliases, func(a Alias) bool { return a.Path == aliasPath }) {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	if len(aliases) == 0 {
+		fmt.Println(locale.Get("alias.noneFound"))
+		return nil
+	}
+
+	var failed []error
+
+	for _, alias := range aliases {
+		if err := os.Remove(alias.Path); err != nil {
+			logger.ErrorLogger.Println("Failed to remove alias:", alias.Path, err)
+			failed = append(failed, err)
+			continue
+		}
+
+		logger.InfoLogger.Println("Removed alias:", alias.Path)
+		fmt.Println(locale.Get("alias.removed", color.GreenString(alias.Path)))
+	}
+
+	fmt.Println(color.HiBlackString(locale.Get("alias.shellAliases")))
+
+	return errors.Join(failed...)
+}
+
+func PrintAliasStatus() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	aliases := FindAliases()
+
+	if len(aliases) == 0 {
+		fmt.Println(locale.Get("alias.noneFoundInstall", color.CyanString("igitt alias install")))
+		return nil
+	}
+
+	for _, alias := range aliases {
+		state := color.HiGreenString(plain.Label("✓", locale.Get("plain.ok")) + " " + locale.Get("alias.upToDate"))
+		switch {
+		case alias.IsStale():
+			state = color.HiRedString(plain.Label("✗", locale.Get("plain.failed")) + " " + locale.Get("alias.outdated"))
+		case alias.Target != executable:
+			state = color.HiYellowString(plain.Label("!", locale.Get("plain.warning")) + " " + locale.Get("alias.differentTarget"))
+		}
+
+		fmt.Printf("%s  %s\n", color.BlueString(alias.Path), state)
+		fmt.Println("    " + locale.Get("alias.target", alias.Kind, alias.Target))
+	}
+
+	if slices.ContainsFunc(aliases, Alias.IsStale) {
+		fmt.Println("\n" + locale.Get("alias.updateHint", color.CyanString("igitt alias install")))
+	}
+
+	return nil
+}
+
+// PrintShellAlias prints a line for the rc file of the shell, for when no
+// directory on the PATH is writable.
+func PrintShellAlias(shell string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	quoted := "'" + strings.ReplaceAll(executable, "'", `'\''`) + "'"
+
+	switch shell {
+	case "bash":
+		fmt.Printf("# add to ~/.bashrc\nalias %s=%s\n", aliasName, quoted)
+	case "zsh":
+		fmt.Printf("# add to ~/.zshrc\nalias %s=%s\n", aliasName, quoted)
+	case "fish":
+		fmt.Printf("# add to ~/.config/fish/config.fish\nalias %s %s\n", aliasName, quoted)
+	default:
+		return errors.New(locale.Get("alias.unknownShell", shell))
 	}
 
-	fmt.Printf("Alias script created successfully at %v\n", color.GreenString(aliasFileName))
-	logger.InfoLogger.Printf("Alias script created successfully at %v", aliasFileName)
+	return nil
 }
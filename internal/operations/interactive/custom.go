@@ -0,0 +1,238 @@
+package interactive
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/operations/git"
+	"github.com/nstr-dev/igitt/internal/utilities/config"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/plugins"
+)
+
+const customCommandPrefix = "custom-"
+const pluginCommandPrefix = "plugin-"
+const defaultCustomIcon = "•"
+const defaultCustomIconAscii = "*"
+
+func getCustomCommand(custom config.CustomCommand) Command {
+	command := Command{
+		Id:              customCommandPrefix + custom.Id,
+		Icon:            custom.Icon,
+		IconEmoji:       custom.IconEmoji,
+		IconNerdFont:    custom.IconNerdFont,
+		IconAscii:       custom.IconAscii,
+		Name:            custom.Name,
+		Shortcut:        custom.Shortcut,
+		Description:     custom.Description,
+		NextStep:        "none",
+		InsideRepoOnly:  custom.InsideRepoOnly,
+		OutsideRepoOnly: custom.OutsideRepoOnly,
+		custom:          &custom,
+	}
+
+	if command.Icon == "" {
+		command.Icon = defaultCustomIcon
+	}
+	if command.IconEmoji == "" {
+		command.IconEmoji = command.Icon
+	}
+	if command.IconNerdFont == "" {
+		command.IconNerdFont = command.Icon
+	}
+	if command.IconAscii == "" {
+		command.IconAscii = defaultCustomIconAscii
+	}
+
+	if command.Description == "" {
+		command.Description = locale.Plural(len(custom.Steps), "custom.description")
+	}
+
+	if len(custom.Prompts) > 0 {
+		command.NextStep = "ns-custom-prompts"
+		command.NextStepTitle = getPromptTitle(custom.Prompts[0])
+	}
+
+	return command
+}
+
+// getPluginCommand turns a plugin into a menu entry, only plugins with a
+// manifest are shown.
+func getPluginCommand(plugin plugins.Plugin) (Command, bool) {
+	if plugin.Manifest == nil {
+		return Command{}, false
+	}
+
+	command := getCustomCommand(config.CustomCommand{
+		Id:              plugin.Name,
+		Name:            plugin.GetMenuName(),
+		Description:     plugin.GetDescription(),
+		Icon:            plugin.Manifest.Icon,
+		IconEmoji:       plugin.Manifest.IconEmoji,
+		IconNerdFont:    plugin.Manifest.IconNerdFont,
+		IconAscii:       plugin.Manifest.IconAscii,
+		Shortcut:        plugin.Manifest.Shortcut,
+		InsideRepoOnly:  plugin.Manifest.InsideRepoOnly,
+		OutsideRepoOnly: plugin.Manifest.OutsideRepoOnly,
+	})
+	command.Id = pluginCommandPrefix + plugin.Name
+	command.custom = nil
+	command.plugin = &plugin
+
+	return command, true
+}
+
+// addCustomCommands puts the user's commands and the plugins in front of the
+// settings entry, which stays last in the menu.
+func addCustomCommands(allCommands []Command, customCommands []config.CustomCommand, discoveredPlugins []plugins.Plugin) []Command {
+	var commands []Command
+	for _, custom := range customCommands {
+		commands = append(commands, getCustomCommand(custom))
+	}
+	for _, plugin := range discoveredPlugins {
+		if command, shown := getPluginCommand(plugin); shown {
+			commands = append(commands, command)
+		}
+	}
+
+	index := slices.IndexFunc(allCommands, func(command Command) bool {
+		return command.Id == "igitt-config"
+	})
+	if index == -1 {
+		index = len(allCommands)
+	}
+
+	return slices.Insert(allCommands, index, commands...)
+}
+
+func getPromptTitle(prompt config.CommandPrompt) string {
+	if prompt.Title != "" {
+		return prompt.Title
+	}
+	return prompt.Name
+}
+
+func runCustomPrompts(custom *config.CustomCommand) error {
+	values := make([]string, len(custom.Prompts))
+	var fields []huh.Field
+
+	for i, prompt := range custom.Prompts {
+		values[i] = prompt.Default
+
+		description := "\n"
+		if prompt.Description != "" {
+			description = "\n  " + prompt.Description + "\n"
+		}
+
+		// accessible mode does not prefill the default, an empty answer
+		// takes it instead
+		defaultAllowed := plain.Enabled && prompt.Default != ""
+		if defaultAllowed {
+			description += locale.Get("custom.plain.default", prompt.Default) + "\n"
+		}
+
+		title := getPromptTitle(prompt)
+		fields = append(fields, huh.NewInput().
+			Title(plain.Title(title, description)).
+			Description(description).
+			Suggestions(prompt.Suggestions).
+			Validate(func(s string) error {
+				if s == "" && !defaultAllowed {
+					return errors.New(locale.Get("custom.emptyValue", title))
+				}
+				return nil
+			}).
+			Value(&values[i]))
+	}
+
+	err := newForm(huh.NewGroup(fields...)).Run()
+	if err != nil {
+		return err
+	}
+
+	for i, prompt := range custom.Prompts {
+		if values[i] == "" {
+			values[i] = prompt.Default
+		}
+		commandFlowResult.CustomParameters[prompt.Name] = values[i]
+	}
+
+	return nil
+}
+
+// runCustomCommand runs the steps in order and stops at the first one that
+// fails, the later steps usually depend on it.
+func runCustomCommand(custom *config.CustomCommand, parameters map[string]string) error {
+	logger.InfoLogger.Printf("custom command %s selected, running %d step(s)\n", custom.Id, len(custom.Steps))
+
+	for i, step := range custom.Steps {
+		description := step.Description
+		if description == "" && len(step.Git) > 0 {
+			description = git.FormatGitCommand(step.FillParameters(parameters))
+		}
+		if description == "" {
+			description = step.Shell
+		}
+
+		fmt.Printf("%s %s\n", color.HiBlackString(fmt.Sprintf("[%d/%d]", i+1, len(custom.Steps))), description)
+
+		var err error
+		if len(step.Git) > 0 {
+			err = git.RunCustomGitStep(step.FillParameters(parameters))
+		} else {
+			err = git.RunCustomShellStep(step.Shell, config.GetParameterEnvironment(parameters))
+		}
+
+		if err != nil {
+			if remaining := len(custom.Steps) - i - 1; remaining > 0 {
+				fmt.Println(color.HiYellowString(locale.Plural(remaining, "custom.stopped", custom.Name)))
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunCustomCommand runs a custom command without the menu, for scripts.
+// Prompts that are not given in parameters take their default.
+func RunCustomCommand(id string, parameters map[string]string) error {
+	customCommands := config.GetConfig().Commands
+
+	index := slices.IndexFunc(customCommands, func(custom config.CustomCommand) bool {
+		return custom.Id == id
+	})
+	if index == -1 {
+		if untrustedPath := config.GetUntrustedRepoConfig(); untrustedPath != "" {
+			return errors.New(locale.Get("custom.notFoundUntrusted", id, untrustedPath))
+		}
+		return errors.New(locale.Get("custom.notFound", id))
+	}
+	custom := customCommands[index]
+
+	values := make(map[string]string)
+
+	for _, prompt := range custom.Prompts {
+		value, given := parameters[prompt.Name]
+		if !given {
+			value = prompt.Default
+		}
+		if value == "" {
+			return errors.New(locale.Get("custom.missingParameter", custom.Name, prompt.Name))
+		}
+		values[prompt.Name] = value
+	}
+
+	for name := range parameters {
+		if _, known := values[name]; !known {
+			return errors.New(locale.Get("custom.unknownParameter", custom.Name, name))
+		}
+	}
+
+	return runCustomCommand(&custom, values)
+}
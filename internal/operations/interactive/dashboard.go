@@ -0,0 +1,109 @@
+package interactive
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/operations/git"
+	"github.com/nstr-dev/igitt/internal/utilities/icons"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+)
+
+// getDashboard describes the current repository above the main menu, it is
+// empty outside a repository.
+func getDashboard() string {
+	summary, isRepo := git.GetRepoSummary()
+	if !isRepo {
+		return ""
+	}
+
+	if plain.Enabled {
+		return getPlainDashboard(summary)
+	}
+
+	variant := getIconVariantFromConfig()
+	arrow, aheadIcon, behindIcon, separator := "→", "↑", "↓", " · "
+	if variant == Ascii {
+		arrow, aheadIcon, behindIcon, separator = "->", "+", "-", " | "
+	}
+
+	var lines []string
+
+	lines = append(lines, color.New(color.Bold, color.FgGreen).Sprint(summary.Name)+"  "+color.HiBlackString(summary.Root))
+
+	branch := summary.Branch
+	if branch == "(detached)" {
+		branch = locale.Get("dashboard.detached")
+	}
+	branchLine := icons.GetBranchIcon(variant) + "  " + color.CyanString(branch)
+	if summary.Upstream != "" {
+		branchLine += " " + arrow + " " + color.HiBlackString(summary.Upstream)
+
+		ahead := fmt.Sprintf("%s%d", aheadIcon, summary.Ahead)
+		behind := fmt.Sprintf("%s%d", behindIcon, summary.Behind)
+		if summary.Ahead > 0 {
+			ahead = color.HiYellowString(ahead)
+		}
+		if summary.Behind > 0 {
+			behind = color.HiYellowString(behind)
+		}
+		branchLine += "  " + ahead + " " + behind
+	} else if summary.Branch != "(detached)" {
+		branchLine += "  " + color.HiBlackString(locale.Get("dashboard.noUpstream"))
+	}
+	lines = append(lines, branchLine)
+
+	changes := []string{
+		color.HiGreenString(locale.Plural(summary.Staged, "dashboard.staged")),
+		color.HiYellowString(locale.Plural(summary.Unstaged, "dashboard.unstaged")),
+		color.HiBlackString(locale.Plural(summary.Untracked, "dashboard.untracked")),
+		locale.Plural(summary.Stashes, "dashboard.stashes"),
+	}
+	if summary.Conflicted > 0 {
+		changes = append([]string{color.HiRedString(locale.Plural(summary.Conflicted, "dashboard.conflicts"))}, changes...)
+	}
+	lines = append(lines, strings.Join(changes, color.HiBlackString(separator)))
+
+	if summary.LastCommit != "" {
+		lines = append(lines, icons.GetCommitIcon(variant)+color.HiBlackString(locale.Get("dashboard.lastCommit")+" ")+summary.LastCommit)
+	}
+
+	return " " + strings.Join(lines, "\n ")
+}
+
+// getPlainDashboard puts a label in front of every line instead of icons
+// and colors, for screen readers and captured output.
+func getPlainDashboard(summary git.RepoSummary) string {
+	lines := []string{locale.Get("dashboard.plain.repository", summary.Name, summary.Root)}
+
+	branch := summary.Branch
+	if branch == "(detached)" {
+		branch = locale.Get("dashboard.detached")
+	}
+	branchLine := locale.Get("dashboard.plain.branch", branch)
+	if summary.Upstream != "" {
+		branchLine += ", " + locale.Get("dashboard.plain.upstream", summary.Upstream, summary.Ahead, summary.Behind)
+	} else if summary.Branch != "(detached)" {
+		branchLine += ", " + locale.Get("dashboard.noUpstream")
+	}
+	lines = append(lines, branchLine)
+
+	changes := []string{
+		locale.Plural(summary.Staged, "dashboard.staged"),
+		locale.Plural(summary.Unstaged, "dashboard.unstaged"),
+		locale.Plural(summary.Untracked, "dashboard.untracked"),
+		locale.Plural(summary.Stashes, "dashboard.stashes"),
+	}
+	if summary.Conflicted > 0 {
+		changes = append([]string{locale.Plural(summary.Conflicted, "dashboard.conflicts")}, changes...)
+	}
+	lines = append(lines, locale.Get("dashboard.plain.changes", strings.Join(changes, ", ")))
+
+	if summary.LastCommit != "" {
+		lines = append(lines, locale.Get("dashboard.lastCommit")+" "+summary.LastCommit)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -2,9 +2,13 @@ package interactive
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/fatih/color"
@@ -12,7 +16,11 @@ import (
 	"github.com/nstr-dev/igitt/internal/utilities"
 	"github.com/nstr-dev/igitt/internal/utilities/config"
 	"github.com/nstr-dev/igitt/internal/utilities/icons"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/plugins"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
 	"github.com/rivo/uniseg"
 
 	_ "embed"
@@ -41,6 +49,11 @@ type Command struct {
 	NextStepTitle   string `json:"nextStepTitle"`
 	InsideRepoOnly  bool   `json:"insideRepoOnly"`
 	OutsideRepoOnly bool   `json:"outsideRepoOnly"`
+
+	// custom is set for the commands from the user's configuration and
+	// plugin for the plugins with a manifest
+	custom *config.CustomCommand
+	plugin *plugins.Plugin
 }
 
 type CommandFlowResult struct {
@@ -53,14 +66,31 @@ type CommandFlowResult struct {
 	NewBranchName       string
 	DeleteBranchConfirm bool
 	SyncWithRemote      bool
+	CloneSubmodules     bool
+	SelectedWorktree    string
+	WorktreeDirty       bool
+	WorktreeBranch      string
+	WorktreePath        string
+	NewWorktreeBranch   string
+	RemoveWorktree      bool
+	ForceWorktreeRemove bool
+	UndoConfirm         bool
+	CustomParameters    map[string]string
 }
 
 const iconWidth = 3
-const shortcutsEnabled = false
 
 func getIconVariantFromConfig() icons.IconType {
+	if plain.Enabled {
+		return Ascii
+	}
+
 	config := config.GetConfig()
-	userIconType := strings.ToLower(config.IconType)
+	return getIconVariant(config.IconType)
+}
+
+func getIconVariant(iconType string) icons.IconType {
+	userIconType := strings.ToLower(iconType)
 
 	if userIconType == "emoji" {
 		return Emoji
@@ -82,14 +112,36 @@ func getShowAllCommandsFromConfig() bool {
 	return config.ShowAllCommands
 }
 
+func getShowDashboardFromConfig() bool {
+	config := config.GetConfig()
+	return config.ShowDashboard
+}
+
+func getStayInMenuFromConfig() bool {
+	config := config.GetConfig()
+	return config.StayInMenu
+}
+
+func getShortcutsFromConfig() map[string]string {
+	config := config.GetConfig()
+	return config.Shortcuts
+}
+
 func getTitle(command Command) string {
-	if getIconVariantFromConfig() == Emoji {
+	return getTitleWithVariant(command, getIconVariantFromConfig())
+}
+
+func getTitleWithVariant(command Command, variant icons.IconType) string {
+	if plain.Enabled {
+		return command.Name
+	}
+	if variant == Emoji {
 		return command.IconEmoji + strings.Repeat(" ", iconWidth-uniseg.StringWidth(command.IconEmoji)) + command.Name
 	}
-	if getIconVariantFromConfig() == NerdFont {
+	if variant == NerdFont {
 		return command.IconNerdFont + strings.Repeat(" ", iconWidth-uniseg.StringWidth(command.IconNerdFont)) + command.Name
 	}
-	if getIconVariantFromConfig() == Ascii {
+	if variant == Ascii {
 		return command.IconAscii + strings.Repeat(" ", iconWidth-uniseg.StringWidth(command.Icon)) + command.Name
 	}
 	return command.Icon + strings.Repeat(" ", iconWidth-uniseg.StringWidth(command.Icon)) + command.Name
@@ -112,21 +164,16 @@ func getBranchOptions() []huh.Option[string] {
 		branchOptions[i] = huh.NewOption(b, b)
 	}
 
-	branchOptions = append(branchOptions, huh.NewOption("[ Create new branch ]", "[newBranch]"))
+	branchOptions = append(branchOptions, huh.NewOption(locale.Get("menu.createBranch"), "[newBranch]"))
 
 	return branchOptions
 }
 
 func getBranchActionOptions() []huh.Option[string] {
-	branchActions := []string{"Check out", "Delete"}
-
-	branchActionOptions := make([]huh.Option[string], len(branchActions))
-
-	for i, b := range branchActions {
-		branchActionOptions[i] = huh.NewOption(b, b)
+	return []huh.Option[string]{
+		huh.NewOption(locale.Get("menu.branchAction.checkout"), "Check out"),
+		huh.NewOption(locale.Get("menu.branchAction.delete"), "Delete"),
 	}
-
-	return branchActionOptions
 }
 
 func getAddFilesOptions() []huh.Option[string] {
@@ -143,167 +190,473 @@ func getAddFilesOptions() []huh.Option[string] {
 
 	for i, f := range files {
 		display := f.StatusLetter + " " + f.FileName
+		if plain.Enabled {
+			display = git.GetStatusLabel(f.StatusLetter) + ": " + f.FileName
+		}
 		addFilesOptions[i] = huh.NewOption(display, f.FileName)
 	}
 
 	return addFilesOptions
 }
 
-var commandFlowResult = CommandFlowResult{
-	SelectedCommand:     Command{Id: "none"},
-	RepoUrlInput:        "",
-	GitAddArguments:     []string{},
-	CommitMessage:       "",
-	NewBranchName:       "",
-	SelectedBranch:      "",
-	BranchAction:        "",
-	DeleteBranchConfirm: false,
-	SyncWithRemote:      false,
+func getWorktreeOptions() []huh.Option[string] {
+	if !utilities.CheckIsRepo() {
+		return []huh.Option[string]{}
+	}
+
+	worktrees, err := git.GetWorktrees()
+	if err != nil {
+		return []huh.Option[string]{}
+	}
+
+	maxPathWidth := 0
+	maxBranchWidth := 0
+
+	for _, w := range worktrees {
+		maxPathWidth = max(maxPathWidth, len(w.Path))
+		maxBranchWidth = max(maxBranchWidth, len(git.GetWorktreeBranchLabel(w)))
+	}
+
+	worktreeOptions := []huh.Option[string]{}
+
+	for _, w := range worktrees {
+		if w.Bare {
+			continue
+		}
+		display := fmt.Sprintf("%-*s  %-*s  %s", maxPathWidth, w.Path, maxBranchWidth, git.GetWorktreeBranchLabel(w), git.GetWorktreeStateLabel(w))
+		worktreeOptions = append(worktreeOptions, huh.NewOption(display, w.Path))
+	}
+
+	worktreeOptions = append(worktreeOptions,
+		huh.NewOption(locale.Get("menu.createWorktree"), "[newWorktree]"),
+		huh.NewOption(locale.Get("menu.pruneWorktrees"), "[pruneWorktrees]"),
+	)
+
+	return worktreeOptions
+}
+
+func getWorktreeBranchOptions() []huh.Option[string] {
+	if !utilities.CheckIsRepo() {
+		return []huh.Option[string]{}
+	}
+
+	branches := git.GetBranches().Branches
+
+	branchOptions := make([]huh.Option[string], len(branches))
+
+	for i, b := range branches {
+		branchOptions[i] = huh.NewOption(b, b)
+	}
+
+	branchOptions = append(branchOptions, huh.NewOption(locale.Get("menu.createBranch"), "[newBranch]"))
+
+	return branchOptions
+}
+
+func getWorktreePathSuggestions() []string {
+	repoRoot := utilities.GetRepoRoot()
+	if repoRoot == "" {
+		return []string{}
+	}
+
+	branch := commandFlowResult.WorktreeBranch
+	if branch == "[newBranch]" {
+		branch = commandFlowResult.NewWorktreeBranch
+	}
+
+	branch = strings.NewReplacer("/", "-", "\\", "-").Replace(branch)
+	if branch == "" {
+		return []string{}
+	}
+
+	return []string{filepath.Join(filepath.Dir(repoRoot), filepath.Base(repoRoot)+"-"+branch)}
+}
+
+func validateBranchName(s string) error {
+	if s == "" {
+		return errors.New(locale.Get("menu.branchName.empty"))
+	}
+
+	if strings.ContainsAny(s, " ~^:?*[]\\") ||
+		strings.Contains(s, "\\") ||
+		strings.Contains(s, "//") ||
+		strings.Contains(s, "@{") ||
+		strings.Contains(s, "..") ||
+		s == "@" {
+		return errors.New(locale.Get("menu.branchName.invalid"))
+	}
+
+	return nil
+}
+
+// Plugins are the igitt-<name> plugins registered as subcommands.
+var Plugins []plugins.Plugin
+
+var commandFlowResult = newCommandFlowResult()
+
+func newCommandFlowResult() CommandFlowResult {
+	return CommandFlowResult{
+		SelectedCommand:     Command{Id: "none"},
+		RepoUrlInput:        "",
+		GitAddArguments:     []string{},
+		CommitMessage:       "",
+		NewBranchName:       "",
+		SelectedBranch:      "",
+		BranchAction:        "",
+		DeleteBranchConfirm: false,
+		SyncWithRemote:      false,
+		CloneSubmodules:     false,
+		CustomParameters:    map[string]string{},
+	}
+}
+
+func getTheme() *huh.Theme {
+	theme := theme.GetHuhTheme()
+	theme.Focused.Base.Border(lipgloss.HiddenBorder())
+	theme.Form.Border(lipgloss.NormalBorder())
+
+	return theme
+}
+
+func filterCommands(allCommands []Command, showAllCommands bool) []Command {
+	if showAllCommands {
+		return allCommands
+	}
+
+	var commands []Command
+	isRepo := utilities.CheckIsRepo()
+
+	for _, command := range allCommands {
+		if command.InsideRepoOnly && !isRepo {
+			continue
+		}
+
+		if command.OutsideRepoOnly && isRepo {
+			continue
+		}
+
+		commands = append(commands, command)
+	}
+
+	return commands
 }
 
-func StartInteractive() {
+func StartInteractive() error {
 
 	interactiveTitle := color.New(color.Bold, color.FgGreen).PrintfFunc()
 	var interactiveTitleText string
 	var interactiveByeText string
 
-	if getIconVariantFromConfig() == Ascii {
-		interactiveTitleText = "[ Igitt Interactive ]\n"
-		interactiveByeText = "[ Bye ]\n"
+	if plain.Enabled {
+		interactiveTitleText = locale.Get("menu.title") + "\n"
+		interactiveByeText = locale.Get("menu.bye") + "\n"
+	} else if getIconVariantFromConfig() == Ascii {
+		interactiveTitleText = "[ " + locale.Get("menu.title") + " ]\n"
+		interactiveByeText = "[ " + locale.Get("menu.bye") + " ]\n"
 	} else {
-		interactiveTitleText = "⌜ Igitt Interactive ⌟\n"
-		interactiveByeText = "⌞ Bye ⌝\n"
+		interactiveTitleText = "⌜ " + locale.Get("menu.title") + " ⌟\n"
+		interactiveByeText = "⌞ " + locale.Get("menu.bye") + " ⌝\n"
+	}
+
+	// huh's accessible mode reads the answers from stdin until it gets a
+	// valid one, it would never stop at the end of a pipe
+	if plain.Enabled && !utilities.IsTerminalInput() {
+		return errors.New(locale.Get("menu.noTerminal"))
 	}
 
 	interactiveTitle(interactiveTitleText)
-	var allCommands []Command
-	var commands []Command
+	utilities.OfferErrorFixes = true
 
-	formGroups := make(map[string]*huh.Form)
+	if git.DryRun {
+		fmt.Println(color.HiBlackString(locale.Get("menu.dryRun") + "\n"))
+	}
 
-	err := json.Unmarshal(commandJSON, &allCommands)
+	var reportedProblems []string
+	var banner string
 
-	if err != nil {
-		logger.ErrorLogger.Fatal(err)
-	}
+	for {
+		commandFlowResult = newCommandFlowResult()
 
-	for _, command := range allCommands {
-		if getShowAllCommandsFromConfig() {
-			commands = allCommands
-			break
+		commands, problems, err := getMenuCommands()
+		if err != nil {
+			logger.ErrorLogger.Println(err)
+			return err
 		}
 
-		if command.InsideRepoOnly && !utilities.CheckIsRepo() {
-			continue
+		// the problems are shown again only if editing the settings changed them
+		if !slices.Equal(problems, reportedProblems) {
+			for _, problem := range problems {
+				logger.WarningLogger.Println(problem)
+				fmt.Println(color.HiYellowString(problem))
+			}
+			if len(problems) > 0 {
+				fmt.Println()
+			}
+			reportedProblems = problems
+		}
+
+		err = runMenu(commands, banner)
+		if errors.Is(err, errMenuClosed) {
+			fmt.Println(color.HiRedString(interactiveByeText))
+			return nil
 		}
 
-		if command.OutsideRepoOnly && utilities.CheckIsRepo() {
+		// the settings editor always leads back to the menu
+		if commandFlowResult.SelectedCommand.Id == "igitt-config" && err == nil {
+			banner = ""
 			continue
 		}
 
-		commands = append(commands, command)
+		if !getStayInMenuFromConfig() {
+			if errors.Is(err, huh.ErrUserAborted) {
+				fmt.Println(color.HiRedString(interactiveByeText))
+				return nil
+			}
+			return err
+		}
+
+		banner = getResultBanner(commandFlowResult.SelectedCommand, err)
 	}
+}
 
-	commandOptions := make([]huh.Option[Command], len(commands))
+// getMenuCommands reads the entries of the main menu again for every round,
+// so they match the repository after the last operation.
+func getMenuCommands() ([]Command, []string, error) {
+	var allCommands []Command
+
+	if err := json.Unmarshal(commandJSON, &allCommands); err != nil {
+		return nil, nil, err
+	}
+
+	translateCommands(allCommands)
+	allCommands = addCustomCommands(allCommands, config.GetConfig().Commands, Plugins)
+	allCommands, problems := resolveShortcuts(allCommands, getShortcutsFromConfig())
 
-	for i, c := range commands {
-		if c.Shortcut == "none" || !shortcutsEnabled {
-			formattedTitle := " " + getTitle(c)
-			commandOptions[i] = huh.NewOption(formattedTitle, c)
-		} else {
-			formattedTitle := fmt.Sprintf("%-15s(%s)", " "+getTitle(c), c.Shortcut)
-			commandOptions[i] = huh.NewOption(formattedTitle, c)
+	if untrustedPath := config.GetUntrustedRepoConfig(); untrustedPath != "" {
+		problems = append(problems, locale.Get("trust.untrusted", untrustedPath))
+	}
+
+	return filterCommands(allCommands, getShowAllCommandsFromConfig()), problems, nil
+}
+
+// translateCommands replaces the English texts of operations.json with the
+// ones of the current language, ids and shortcuts stay the same.
+func translateCommands(commands []Command) {
+	for i := range commands {
+		prefix := "operation." + commands[i].Id + "."
+
+		if name, found := locale.Lookup(prefix + "name"); found {
+			commands[i].Name = name
+		}
+		if description, found := locale.Lookup(prefix + "description"); found {
+			commands[i].Description = description
+		}
+		if nextStepTitle, found := locale.Lookup(prefix + "nextStepTitle"); found {
+			commands[i].NextStepTitle = nextStepTitle
 		}
 	}
+}
 
-	theme := huh.ThemeCatppuccin()
-	theme.Focused.Base.Border(lipgloss.HiddenBorder())
-	theme.Form.Border(lipgloss.NormalBorder())
+// newForm builds a form of the interactive mode, plain output runs it in
+// huh's accessible mode.
+func newForm(groups ...*huh.Group) *huh.Form {
+	return huh.NewForm(groups...).WithTheme(getTheme()).WithAccessible(plain.Enabled)
+}
 
-	formGroups["ns-ask-sync"] =
-		huh.NewForm(
+var branchNameSuggestions = []string{
+	"feature/",
+	"bugfix/",
+	"hotfix/",
+	"fix/",
+	"refactor/",
+	"chore/",
+	"docs/",
+}
+
+// getFormGroups returns the forms of the next steps. Each one is built right
+// before it runs, so its options and descriptions follow the answers given
+// so far, even in huh's accessible mode, which asks every question of a
+// form and loads no options later.
+func getFormGroups() map[string]func() *huh.Form {
+	formGroups := make(map[string]func() *huh.Form)
+
+	formGroups["ns-ask-sync"] = func() *huh.Form {
+		description := "\n  " + locale.Get("menu.sync.description") + "\n"
+
+		return newForm(
 			huh.NewGroup(
 				huh.NewConfirm().
-					Title("Sync with remote").
-					Description("\n  Do you want to sync with the remote repository?\n").
-					Value(&commandFlowResult.SyncWithRemote))).WithTheme(theme)
+					Title(plain.Title(locale.Get("menu.sync.title"), description)).
+					Description(description).
+					Value(&commandFlowResult.SyncWithRemote)))
+	}
+
+	formGroups["ns-choose-branch"] = func() *huh.Form {
+		description := "\n  " + locale.Get("menu.branch.description") + "\n"
 
-	formGroups["ns-choose-branch"] =
-		huh.NewForm(
+		return newForm(
 			huh.NewGroup(
 				huh.NewSelect[string]().
-					Title("Branch selection").
-					Description("\n  Select a branch\n").
-					OptionsFunc(getBranchOptions, &commandFlowResult.SelectedCommand).
-					Value(&commandFlowResult.SelectedBranch))).WithTheme(theme)
+					Title(plain.Title(locale.Get("menu.branch.title"), description)).
+					Description(description).
+					Options(getBranchOptions()...).
+					Value(&commandFlowResult.SelectedBranch)))
+	}
+
+	formGroups["ns-choose-add-files"] = func() *huh.Form {
+		description := "\n  " + locale.Get("menu.stage.description") + "\n"
 
-	formGroups["ns-choose-add-files"] =
-		huh.NewForm(
+		return newForm(
 			huh.NewGroup(
 				huh.NewMultiSelect[string]().
-					OptionsFunc(getAddFilesOptions, &commandFlowResult.SelectedCommand).
-					Title("Stage files").
-					Description("\n  Select files to stage (ctrl + a to select all)\n").
-					Value(&commandFlowResult.GitAddArguments))).WithTheme(theme)
+					Options(getAddFilesOptions()...).
+					Title(plain.Title(locale.Get("menu.stage.title"), description)).
+					Description(description).
+					Value(&commandFlowResult.GitAddArguments)))
+	}
+
+	formGroups["ns-choose-branch-action"] = func() *huh.Form {
+		description := "\n  " + locale.Get("menu.branchAction.description", commandFlowResult.SelectedBranch) + "\n"
 
-	formGroups["ns-choose-branch-action"] =
-		huh.NewForm(
+		return newForm(
 			huh.NewGroup(
 				huh.NewSelect[string]().
-					Title("Branch action selection").
-					Description(fmt.Sprintf("\n  Select an action for the branch %s\n", commandFlowResult.SelectedBranch)).
+					Title(plain.Title(locale.Get("menu.branchAction.title"), description)).
+					Description(description).
 					Options(getBranchActionOptions()...).
-					Value(&commandFlowResult.BranchAction)),
+					Value(&commandFlowResult.BranchAction)))
+	}
+
+	formGroups["ns-confirm-delete-branch"] = func() *huh.Form {
+		description := "\n  " + locale.Get("menu.deleteBranch.description", commandFlowResult.SelectedBranch) + "\n"
+
+		return newForm(
 			huh.NewGroup(
 				huh.NewConfirm().
-					Title("Delete branch").
-					Description(fmt.Sprintf("\n  Are you sure you want to delete the branch %s?\n", commandFlowResult.SelectedBranch)).
-					Value(&commandFlowResult.DeleteBranchConfirm),
-			).WithHideFunc(func() bool {
-				return commandFlowResult.DeleteBranchConfirm ||
-					commandFlowResult.SelectedCommand.NextStep != "ns-choose-branch-action" ||
-					commandFlowResult.BranchAction != "Delete"
-			})).WithTheme(theme)
-
-	formGroups["ns-enter-new-branch-name"] =
-		huh.NewForm(
+					Title(plain.Title(locale.Get("menu.deleteBranch.title"), description)).
+					Description(description).
+					Value(&commandFlowResult.DeleteBranchConfirm)))
+	}
+
+	formGroups["ns-enter-new-branch-name"] = func() *huh.Form {
+		description := "\n" + locale.Get("menu.branchName.description") + "\n"
+
+		return newForm(
 			huh.NewGroup(
 				huh.NewInput().
-					Title("Branch name").
-					Description("\n" + "Enter the desired branch name here.\n").
-					Suggestions([]string{
-						"feature/",
-						"bugfix/",
-						"hotfix/",
-						"fix/",
-						"refactor/",
-						"chore/",
-						"docs/",
-					}).
-					Validate(func(s string) error {
-						if s == "" {
-							return fmt.Errorf("the branch name should not be empty")
-						}
+					Title(plain.Title(locale.Get("menu.branchName.title"), description)).
+					Description(description).
+					Suggestions(branchNameSuggestions).
+					Validate(validateBranchName).
+					Value(&commandFlowResult.NewBranchName)))
+	}
 
-						if strings.ContainsAny(s, " ~^:?*[]\\") ||
-							strings.Contains(s, "\\") ||
-							strings.Contains(s, "//") ||
-							strings.Contains(s, "@{") ||
-							strings.Contains(s, "..") ||
-							s == "@" {
-							return fmt.Errorf("some special characters are not allowed in branch names")
-						}
+	formGroups["ns-choose-worktree"] = func() *huh.Form {
+		description := "\n  " + locale.Get("menu.worktree.description") + "\n"
+
+		return newForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title(plain.Title(locale.Get("menu.worktree.title"), description)).
+					Description(description).
+					Options(getWorktreeOptions()...).
+					Value(&commandFlowResult.SelectedWorktree)))
+	}
+
+	formGroups["ns-confirm-remove-worktree"] = func() *huh.Form {
+		description := "\n  " + locale.Get("menu.removeWorktree.description", commandFlowResult.SelectedWorktree) + "\n"
+
+		return newForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title(plain.Title(locale.Get("menu.removeWorktree.title"), description)).
+					Description(description).
+					Value(&commandFlowResult.RemoveWorktree)))
+	}
+
+	formGroups["ns-confirm-remove-dirty-worktree"] = func() *huh.Form {
+		description := "\n  " + locale.Get("menu.dirtyWorktree.description", commandFlowResult.SelectedWorktree) + "\n"
+
+		return newForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title(plain.Title(locale.Get("menu.dirtyWorktree.title"), description)).
+					Description(description).
+					Affirmative(locale.Get("menu.dirtyWorktree.remove")).
+					Negative(locale.Get("menu.dirtyWorktree.keep")).
+					Value(&commandFlowResult.ForceWorktreeRemove)))
+	}
+
+	formGroups["ns-enter-new-worktree"] = func() *huh.Form {
+		description := "\n  " + locale.Get("menu.worktreeBranch.description") + "\n"
 
+		return newForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title(plain.Title(locale.Get("menu.worktreeBranch.title"), description)).
+					Description(description).
+					Options(getWorktreeBranchOptions()...).
+					Value(&commandFlowResult.WorktreeBranch)))
+	}
+
+	formGroups["ns-enter-new-worktree-branch"] = func() *huh.Form {
+		description := "\n" + locale.Get("menu.branchName.description") + "\n"
+
+		return newForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title(plain.Title(locale.Get("menu.branchName.title"), description)).
+					Description(description).
+					Suggestions(branchNameSuggestions).
+					Validate(validateBranchName).
+					Value(&commandFlowResult.NewWorktreeBranch)))
+	}
+
+	formGroups["ns-enter-worktree-path"] = func() *huh.Form {
+		description := "\n" + locale.Get("menu.worktreePath.description") + "\n"
+		suggestions := getWorktreePathSuggestions()
+
+		// accessible mode offers no suggestions, so an empty answer takes
+		// the one igitt would suggest
+		if plain.Enabled && len(suggestions) > 0 {
+			description += locale.Get("menu.worktreePath.default", suggestions[0]) + "\n"
+		}
+
+		return newForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title(plain.Title(locale.Get("menu.worktreePath.title"), description)).
+					Description(description).
+					Suggestions(suggestions).
+					Validate(func(s string) error {
+						if s == "" && !(plain.Enabled && len(suggestions) > 0) {
+							return errors.New(locale.Get("menu.worktreePath.empty"))
+						}
 						return nil
 					}).
-					Value(&commandFlowResult.NewBranchName))).WithTheme(theme)
+					Value(&commandFlowResult.WorktreePath)))
+	}
 
-	formGroups["ns-enter-repo-url"] =
-		huh.NewForm(
+	formGroups["ns-confirm-undo"] = func() *huh.Form {
+		description := "\n" + git.GetUndoDescription() + "\n"
+
+		return newForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title(plain.Title(locale.Get("menu.undo.title"), description)).
+					Description(description).
+					Value(&commandFlowResult.UndoConfirm)))
+	}
+
+	formGroups["ns-enter-repo-url"] = func() *huh.Form {
+		description := "\n" + icons.GetLinkIcon(getIconVariantFromConfig()) + locale.Get("menu.clone.description") + "\n"
+		submodulesDescription := "\n  " + locale.Get("menu.cloneSubmodules.description") + "\n"
+
+		return newForm(
 			huh.NewGroup(
 				huh.NewInput().
-					Title("Link to Git repository").
-					Description("\n" + icons.GetLinkIcon(getIconVariantFromConfig()) + "Enter the link to your repository here.\n").
+					Title(plain.Title(locale.Get("menu.clone.title"), description)).
+					Description(description).
 					Suggestions([]string{
 						"https://github.com/",
 						"https://gitlab.com/",
@@ -314,35 +667,42 @@ func StartInteractive() {
 					}).
 					Validate(func(s string) error {
 						if s == "" {
-							return fmt.Errorf("if you're ready to clone, enter a repository URL")
+							return errors.New(locale.Get("menu.clone.empty"))
 						}
 						return nil
 					}).
-					Value(&commandFlowResult.RepoUrlInput))).WithTheme(theme)
+					Value(&commandFlowResult.RepoUrlInput),
+				huh.NewConfirm().
+					Title(plain.Title(locale.Get("menu.cloneSubmodules.title"), submodulesDescription)).
+					Description(submodulesDescription).
+					Value(&commandFlowResult.CloneSubmodules)))
+	}
+
+	formGroups["ns-enter-commit-message"] = func() *huh.Form {
+		description := "\n" +
+			icons.GetCommitIcon(getIconVariantFromConfig()) +
+			locale.Get("menu.commit.description") + "\n\n" +
+			icons.GetBranchIcon(getIconVariantFromConfig()) + "  " + func() string {
+			if !utilities.CheckIsRepo() {
+				return ""
+			}
+			return git.GetBranches().CheckedOutBranch
+		}() + "\n" + func() string {
+			if !utilities.CheckIsRepo() {
+				return ""
+			}
+			count, _ := git.GetStagedModificationCount()
+			if count == 0 {
+				return ""
+			}
+			return "*  " + locale.Get("menu.commit.filesChanged", git.GetStagedModificationCountAsString()) + "\n"
+		}()
 
-	formGroups["ns-enter-commit-message"] =
-		huh.NewForm(
+		return newForm(
 			huh.NewGroup(
 				huh.NewInput().
-					Title("Commit message").
-					Description("\n" +
-						icons.GetCommitIcon(getIconVariantFromConfig()) +
-						"Type a short description to the commit.\n\n" +
-						icons.GetBranchIcon(getIconVariantFromConfig()) + "  " + func() string {
-						if !utilities.CheckIsRepo() {
-							return ""
-						}
-						return git.GetBranches().CheckedOutBranch
-					}() + "\n" + func() string {
-						if !utilities.CheckIsRepo() {
-							return ""
-						}
-						count, _ := git.GetStagedModificationCount()
-						if count == 0 {
-							return ""
-						}
-						return "*  Files changed: " + git.GetStagedModificationCountAsString() + "\n"
-					}()).
+					Title(plain.Title(locale.Get("menu.commit.title"), description)).
+					Description(description).
 					Suggestions([]string{
 						"feat: ",
 						"fix: ",
@@ -355,17 +715,31 @@ func StartInteractive() {
 					}).
 					Validate(func(s string) error {
 						if s == "" {
-							return fmt.Errorf("please enter a commit message")
+							return errors.New(locale.Get("menu.commit.empty"))
 						}
 						return nil
 					}).
-					Value(&commandFlowResult.CommitMessage))).WithTheme(theme)
+					Value(&commandFlowResult.CommitMessage)))
+	}
 
-	mainForm := huh.NewForm(
+	return formGroups
+}
+
+// runMenu shows the main menu once and runs the chosen operation.
+func runMenu(commands []Command, banner string) error {
+	commandOptions := make([]huh.Option[Command], len(commands))
+
+	for i, title := range getOptionTitles(commands) {
+		commandOptions[i] = huh.NewOption(title, commands[i])
+	}
+
+	selection := &shortcutSelection{value: &commandFlowResult.SelectedCommand}
+	shortcuts := &shortcutFilter{commands: commands, selection: selection}
+
+	mainForm := newForm(
 		huh.NewGroup(
 			huh.NewSelect[Command]().
 				Title("Igitt").
-				Filtering(true).
 				DescriptionFunc(func() string {
 					if commandFlowResult.SelectedCommand.NextStep != "none" {
 						return fmt.Sprintf(
@@ -373,40 +747,57 @@ func StartInteractive() {
 							getTitle(commandFlowResult.SelectedCommand),
 							commandFlowResult.SelectedCommand.Description,
 							icons.GetNextStepIcon(getIconVariantFromConfig()),
-							"Next step: "+commandFlowResult.SelectedCommand.NextStepTitle,
+							locale.Get("menu.nextStep", commandFlowResult.SelectedCommand.NextStepTitle),
 						)
 					}
 
 					return fmt.Sprintf(
-						"\n%s: %s\n\n   %s  %s next steps\n\n",
+						"\n%s: %s\n\n   %s  %s\n\n",
 						getTitle(commandFlowResult.SelectedCommand),
 						commandFlowResult.SelectedCommand.Description,
 						icons.GetNoNextStepIcon(getIconVariantFromConfig()),
-						"No",
+						locale.Get("menu.noNextStep"),
 					)
 				}, &commandFlowResult.SelectedCommand).
 				Options(commandOptions...).
-				Value(&commandFlowResult.SelectedCommand),
+				Accessor(selection),
 		),
-	).WithTheme(theme).WithHeight(len(commands) + 9)
+	).WithHeight(len(commands) + 9).WithProgramOptions(tea.WithFilter(shortcuts.filter))
 
-	err = mainForm.Run()
+	if banner != "" {
+		fmt.Printf("\n%s\n\n", banner)
+	}
+
+	if getShowDashboardFromConfig() {
+		if dashboard := getDashboard(); dashboard != "" {
+			fmt.Printf("%s\n\n", dashboard)
+		}
+	}
+
+	err := mainForm.Run()
+	if errors.Is(err, huh.ErrUserAborted) || commandFlowResult.SelectedCommand.Id == "exit" {
+		return errMenuClosed
+	}
 	if err != nil {
-		fmt.Println(color.HiRedString(interactiveByeText))
-		logger.ErrorLogger.Fatal(err)
+		logger.ErrorLogger.Println(err)
+		return err
 	}
 
-	nextStepErr := runNextStep(formGroups)
+	nextStepErr := runNextStep(getFormGroups())
+	if errors.Is(nextStepErr, huh.ErrUserAborted) {
+		return nextStepErr
+	}
 	if nextStepErr != nil {
-		logger.ErrorLogger.Fatal(nextStepErr)
-	} else {
-		runResultingCommand()
+		logger.ErrorLogger.Println(nextStepErr)
+		return nextStepErr
 	}
+
+	return runResultingCommand()
 }
 
-func runNextStep(formGroups map[string]*huh.Form) error {
+func runNextStep(formGroups map[string]func() *huh.Form) error {
 	if commandFlowResult.SelectedCommand.NextStep == "ns-choose-branch" {
-		err := formGroups["ns-choose-branch"].Run()
+		err := formGroups["ns-choose-branch"]().Run()
 		if err != nil {
 			return err
 		}
@@ -423,37 +814,119 @@ func runNextStep(formGroups map[string]*huh.Form) error {
 	}
 
 	if commandFlowResult.SelectedCommand.NextStep == "ns-enter-new-branch-name" {
-		return formGroups["ns-enter-new-branch-name"].Run()
+		return formGroups["ns-enter-new-branch-name"]().Run()
 	}
 
 	if commandFlowResult.SelectedCommand.NextStep == "ns-choose-branch-action" {
-		return formGroups["ns-choose-branch-action"].Run()
+		err := formGroups["ns-choose-branch-action"]().Run()
+		if err != nil {
+			return err
+		}
+
+		// the checked out branch cannot be deleted, there is nothing to confirm
+		if commandFlowResult.BranchAction != "Delete" || strings.Contains(commandFlowResult.SelectedBranch, "*") {
+			return nil
+		}
+
+		return formGroups["ns-confirm-delete-branch"]().Run()
+	}
+
+	if commandFlowResult.SelectedCommand.NextStep == "ns-choose-worktree" {
+		err := formGroups["ns-choose-worktree"]().Run()
+		if err != nil {
+			return err
+		}
+
+		if commandFlowResult.SelectedWorktree == "[pruneWorktrees]" {
+			return nil
+		}
+
+		if commandFlowResult.SelectedWorktree == "[newWorktree]" {
+			commandFlowResult.SelectedCommand.NextStep = "ns-enter-new-worktree"
+			return runNextStep(formGroups)
+		}
+
+		dirty, err := git.IsWorktreeDirty(commandFlowResult.SelectedWorktree)
+		if err != nil {
+			return err
+		}
+		commandFlowResult.WorktreeDirty = dirty
+
+		commandFlowResult.SelectedCommand.NextStep = "ns-confirm-remove-worktree"
+		return runNextStep(formGroups)
+	}
+
+	if commandFlowResult.SelectedCommand.NextStep == "ns-enter-new-worktree" {
+		err := formGroups["ns-enter-new-worktree"]().Run()
+		if err != nil {
+			return err
+		}
+
+		if commandFlowResult.WorktreeBranch == "[newBranch]" {
+			err = formGroups["ns-enter-new-worktree-branch"]().Run()
+			if err != nil {
+				return err
+			}
+		}
+
+		err = formGroups["ns-enter-worktree-path"]().Run()
+		if err != nil {
+			return err
+		}
+
+		if commandFlowResult.WorktreePath == "" {
+			if suggestions := getWorktreePathSuggestions(); len(suggestions) > 0 {
+				commandFlowResult.WorktreePath = suggestions[0]
+			}
+		}
+
+		return nil
+	}
+
+	if commandFlowResult.SelectedCommand.NextStep == "ns-confirm-remove-worktree" {
+		err := formGroups["ns-confirm-remove-worktree"]().Run()
+		if err != nil {
+			return err
+		}
+
+		if !commandFlowResult.RemoveWorktree || !commandFlowResult.WorktreeDirty {
+			return nil
+		}
+
+		return formGroups["ns-confirm-remove-dirty-worktree"]().Run()
+	}
+
+	if commandFlowResult.SelectedCommand.NextStep == "ns-confirm-undo" {
+		return formGroups["ns-confirm-undo"]().Run()
 	}
 
 	if commandFlowResult.SelectedCommand.NextStep == "ns-enter-repo-url" {
-		return formGroups["ns-enter-repo-url"].Run()
+		return formGroups["ns-enter-repo-url"]().Run()
 	}
 
 	if commandFlowResult.SelectedCommand.NextStep == "ns-enter-commit-message" {
 		modifications, err := git.GetStagedModificationCount()
 
 		if err != nil {
-			logger.ErrorLogger.Fatal(err)
+			return err
 		}
 
 		if modifications == 0 {
-			err = formGroups["ns-choose-add-files"].Run()
+			err = formGroups["ns-choose-add-files"]().Run()
+			if err != nil {
+				return err
+			}
+			err = git.AddChanges(commandFlowResult.GitAddArguments)
 			if err != nil {
-				logger.ErrorLogger.Fatal(err)
+				return err
 			}
-			git.AddChanges(commandFlowResult.GitAddArguments)
 			fmt.Println()
 		}
 
-		err = formGroups["ns-enter-commit-message"].Run()
+		err = formGroups["ns-enter-commit-message"]().Run()
 
 		if err != nil {
-			logger.ErrorLogger.Fatal(err)
+			return err
 		}
 
 		commandFlowResult.SelectedCommand.NextStep = "ns-ask-sync"
@@ -463,35 +936,38 @@ func runNextStep(formGroups map[string]*huh.Form) error {
 	}
 
 	if commandFlowResult.SelectedCommand.NextStep == "ns-choose-add-files" {
-		return formGroups["ns-choose-add-files"].Run()
+		return formGroups["ns-choose-add-files"]().Run()
 	}
 
 	if commandFlowResult.SelectedCommand.NextStep == "ns-ask-sync" {
-		return formGroups["ns-ask-sync"].Run()
+		return formGroups["ns-ask-sync"]().Run()
+	}
+
+	if commandFlowResult.SelectedCommand.NextStep == "ns-custom-prompts" {
+		return runCustomPrompts(commandFlowResult.SelectedCommand.custom)
 	}
 
 	return nil
 }
 
-func runResultingCommand() {
-
+func runResultingCommand() error {
 	if commandFlowResult.SelectedCommand.Id == "op-clone" && commandFlowResult.RepoUrlInput != "" {
 		logger.InfoLogger.Println("clone command selected, sending to operations")
-		git.CloneRepository(commandFlowResult.RepoUrlInput)
-		return
+		return git.CloneRepository(commandFlowResult.RepoUrlInput, commandFlowResult.CloneSubmodules)
 	}
 
 	if commandFlowResult.SelectedCommand.Id == "op-commit" && commandFlowResult.CommitMessage != "" {
 		logger.InfoLogger.Println("commit command selected, sending to operations")
-		git.CommitChanges(commandFlowResult.CommitMessage)
+		if err := git.CommitChanges(commandFlowResult.CommitMessage); err != nil {
+			return err
+		}
 
 		if commandFlowResult.SyncWithRemote {
 			logger.InfoLogger.Println("sync command selected, sending to operations")
-			git.PullRemote()
-			git.PushRemote()
+			return git.SyncWithRemote()
 		}
 
-		return
+		return nil
 	}
 
 	if commandFlowResult.SelectedCommand.Id == "op-branches" &&
@@ -500,8 +976,7 @@ func runResultingCommand() {
 		commandFlowResult.SelectedCommand.NextStep == "ns-enter-new-branch-name" {
 
 		logger.InfoLogger.Printf("branch command selected, sending to operations, new branch name: %s\n", commandFlowResult.NewBranchName)
-		git.CreateBranch(commandFlowResult.NewBranchName)
-		return
+		return git.CreateBranch(commandFlowResult.NewBranchName)
 	}
 
 	if commandFlowResult.SelectedCommand.Id == "op-branches" &&
@@ -515,67 +990,114 @@ func runResultingCommand() {
 
 		if commandFlowResult.BranchAction == "Check out" && !isCheckedOutAlready {
 			logger.InfoLogger.Println("checkout command selected, sending to operations")
-			git.CheckoutBranch(commandFlowResult.SelectedBranch)
-			return
+			return git.CheckoutBranch(commandFlowResult.SelectedBranch)
 		}
 		if commandFlowResult.BranchAction == "Check out" && isCheckedOutAlready {
 			logger.InfoLogger.Println("checkout command selected, not sending to operations, branch already checked out")
-			fmt.Println("Branch already checked out")
-			return
+			fmt.Println(locale.Get("branch.alreadyCheckedOut"))
+			return nil
+		}
+
+		if commandFlowResult.BranchAction == "Delete" && !isCheckedOutAlready && !commandFlowResult.DeleteBranchConfirm {
+			logger.InfoLogger.Println("delete command selected, not sending to operations, deletion not confirmed")
+			fmt.Println(locale.Get("branch.kept"))
+			return nil
 		}
 
 		if commandFlowResult.BranchAction == "Delete" && !isCheckedOutAlready {
 			logger.InfoLogger.Println("delete command selected, sending to operations")
-			git.DeleteBranch(checkedOutBranchWithoutStar)
-			return
+			return git.DeleteBranch(checkedOutBranchWithoutStar)
 		}
 
 		if commandFlowResult.BranchAction == "Delete" && isCheckedOutAlready {
 			logger.InfoLogger.Println("delete command selected, not sending to operations, branch already checked out")
-			fmt.Println("Cannot delete the branch you are currently on")
-			return
+			fmt.Println(locale.Get("branch.cannotDeleteCurrent"))
+			return nil
 		}
 	}
 
+	if commandFlowResult.SelectedCommand.Id == "op-worktrees" && commandFlowResult.SelectedWorktree == "[pruneWorktrees]" {
+		logger.InfoLogger.Println("worktree prune selected, sending to operations")
+		return git.PruneWorktrees()
+	}
+
+	if commandFlowResult.SelectedCommand.Id == "op-worktrees" &&
+		commandFlowResult.SelectedWorktree == "[newWorktree]" &&
+		commandFlowResult.WorktreePath != "" {
+
+		if commandFlowResult.WorktreeBranch == "[newBranch]" {
+			logger.InfoLogger.Printf("worktree add selected with new branch %s, sending to operations\n", commandFlowResult.NewWorktreeBranch)
+			return git.AddWorktree(commandFlowResult.WorktreePath, commandFlowResult.NewWorktreeBranch, true)
+		}
+
+		logger.InfoLogger.Printf("worktree add selected with branch %s, sending to operations\n", commandFlowResult.WorktreeBranch)
+		return git.AddWorktree(commandFlowResult.WorktreePath, commandFlowResult.WorktreeBranch, false)
+	}
+
+	if commandFlowResult.SelectedCommand.Id == "op-worktrees" && commandFlowResult.SelectedWorktree != "" {
+		if !commandFlowResult.RemoveWorktree {
+			logger.InfoLogger.Println("worktree removal not confirmed, not sending to operations")
+			return nil
+		}
+
+		if commandFlowResult.WorktreeDirty && !commandFlowResult.ForceWorktreeRemove {
+			logger.InfoLogger.Println("dirty worktree removal not confirmed, not sending to operations")
+			fmt.Println(locale.Get("worktree.kept"))
+			return nil
+		}
+
+		logger.InfoLogger.Println("worktree remove selected, sending to operations")
+		return git.RemoveWorktree(commandFlowResult.SelectedWorktree, commandFlowResult.WorktreeDirty)
+	}
+
+	if commandFlowResult.SelectedCommand.Id == "op-undo" && commandFlowResult.UndoConfirm {
+		logger.InfoLogger.Println("undo command selected, sending to operations")
+		return git.Undo()
+	}
+
 	if commandFlowResult.SelectedCommand.Id == "op-init" {
 		logger.InfoLogger.Println("init command selected, sending to operations")
-		git.InitRepository()
-		return
+		return git.InitRepository()
 	}
 
 	if commandFlowResult.SelectedCommand.Id == "op-status" {
 		logger.InfoLogger.Println("status command selected, sending to operations")
-		git.Status()
-		return
+		return git.Status()
 	}
 
 	if commandFlowResult.SelectedCommand.Id == "op-pull" {
 		logger.InfoLogger.Println("pull command selected, sending to operations")
-		git.PullRemote()
-		return
+		return git.PullRemote(git.HasSubmodules())
 	}
 
 	if commandFlowResult.SelectedCommand.Id == "op-push" {
 		logger.InfoLogger.Println("push command selected, sending to operations")
-		git.PushRemote()
-		return
+		return git.PushRemote()
 	}
 
 	if commandFlowResult.SelectedCommand.Id == "op-add" && len(commandFlowResult.GitAddArguments) == 0 {
 		logger.InfoLogger.Println("add command selected with no arguments, sending to operations")
-		git.AddEverything()
-		return
+		return git.AddEverything()
 	}
 
 	if commandFlowResult.SelectedCommand.Id == "op-add" && len(commandFlowResult.GitAddArguments) > 0 {
 		logger.InfoLogger.Println("add command selected, sending to operations with arguments", commandFlowResult.GitAddArguments)
-		git.AddChanges(commandFlowResult.GitAddArguments)
-		return
+		return git.AddChanges(commandFlowResult.GitAddArguments)
+	}
+
+	if commandFlowResult.SelectedCommand.plugin != nil {
+		logger.InfoLogger.Printf("plugin %s selected, running it\n", commandFlowResult.SelectedCommand.plugin.Name)
+		return commandFlowResult.SelectedCommand.plugin.Run(nil, git.DryRun)
+	}
+
+	if commandFlowResult.SelectedCommand.custom != nil {
+		return runCustomCommand(commandFlowResult.SelectedCommand.custom, commandFlowResult.CustomParameters)
 	}
 
 	if commandFlowResult.SelectedCommand.Id == "igitt-config" {
-		logger.InfoLogger.Println("config command selected, printing config path")
-		config.GetConfigPath(true)
-		return
+		logger.InfoLogger.Println("config command selected, opening settings editor")
+		return editSettings()
 	}
+
+	return nil
 }
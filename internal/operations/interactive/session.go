@@ -0,0 +1,47 @@
+package interactive
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/operations/git"
+	"github.com/nstr-dev/igitt/internal/utilities/icons"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/plugins"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
+)
+
+// errMenuClosed ends the session, the main menu was left with Esc, ctrl+c
+// or the Exit entry.
+var errMenuClosed = errors.New("the menu was closed")
+
+// getResultBanner sums up the last operation above the menu of the next
+// round. Git and refusal errors were already explained where they happened.
+func getResultBanner(command Command, err error) string {
+	variant := getIconVariantFromConfig()
+
+	if err == nil {
+		return color.HiGreenString(plain.Label(icons.GetSuccessIcon(variant), locale.Get("plain.ok")) + " " + locale.Get("session.finished", command.Name))
+	}
+
+	if errors.Is(err, huh.ErrUserAborted) {
+		return color.HiBlackString(locale.Get("session.cancelled", command.Name))
+	}
+
+	failed := plain.Label(icons.GetFailureIcon(variant), locale.Get("plain.failed")) + " " + locale.Get("session.failed", command.Name)
+
+	var gitError *git.GitError
+	var refusedError *git.RefusedError
+	var pluginError *plugins.ExitError
+
+	switch {
+	case errors.As(err, &gitError), errors.As(err, &refusedError):
+		return theme.ErrorTitle(failed)
+	case errors.As(err, &pluginError):
+		return theme.ErrorTitle(failed + ", " + locale.Get("session.exitCode", pluginError.ExitCode))
+	}
+
+	return theme.ErrorTitle(failed+": ") + theme.ErrorMessage(err.Error())
+}
@@ -0,0 +1,255 @@
+package interactive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/config"
+	"github.com/nstr-dev/igitt/internal/utilities/icons"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
+)
+
+type settingText struct {
+	title       string
+	description string
+}
+
+// getSettingText reads the title and description of a setting from the
+// message catalog, settings without a text show their key.
+func getSettingText(key string) settingText {
+	if !locale.Has("setting." + key + ".title") {
+		return settingText{title: key}
+	}
+
+	return settingText{
+		title:       locale.Get("setting." + key + ".title"),
+		description: locale.Get("setting." + key + ".description"),
+	}
+}
+
+type settingValue struct {
+	setting  config.Setting
+	original string
+	text     string
+	enabled  bool
+}
+
+func (v *settingValue) current() string {
+	if v.setting.Kind == reflect.Bool {
+		return strconv.FormatBool(v.enabled)
+	}
+	return v.text
+}
+
+func getIconPreview(iconType string, allCommands []Command) string {
+	variant := getIconVariant(iconType)
+
+	var preview strings.Builder
+	preview.WriteString("\n  " + locale.Get("settings.preview") + "\n\n")
+
+	for _, command := range allCommands[:min(4, len(allCommands))] {
+		preview.WriteString("    " + getTitleWithVariant(command, variant) + "\n")
+	}
+
+	preview.WriteString(fmt.Sprintf("\n    %s  %s   %s  %s   %s  main\n",
+		icons.GetNextStepIcon(variant),
+		locale.Get("settings.preview.nextStep"),
+		icons.GetNoNextStepIcon(variant),
+		locale.Get("settings.preview.noNextStep"),
+		icons.GetBranchIcon(variant),
+	))
+
+	return preview.String()
+}
+
+func getShowAllCommandsPreview(showAllCommands bool, allCommands []Command) string {
+	commands := filterCommands(allCommands, showAllCommands)
+
+	var names []string
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+
+	return "\n  " + locale.Get("settings.shownCommands", len(commands), len(allCommands)) + "\n  " + strings.Join(names, ", ") + "\n"
+}
+
+// applyPlainAnswer takes over what was typed into the plain form, an empty
+// answer keeps the value.
+func (v *settingValue) applyPlainAnswer() {
+	if v.text == "" {
+		v.text = v.original
+	}
+	if normalized, err := v.setting.Normalize(v.text); err == nil {
+		v.text = normalized
+	}
+	v.enabled = v.text == "true"
+}
+
+// getPlainSettingField asks for every setting as text, huh's accessible
+// mode would answer a confirm with no and cannot keep a prefilled value.
+func getPlainSettingField(value *settingValue, text settingText, description string) huh.Field {
+	description += locale.Get("settings.plain.keep", value.original) + "\n"
+	if len(value.setting.Choices) > 0 {
+		description += locale.Get("settings.plain.choices", strings.Join(value.setting.Choices, ", ")) + "\n"
+	}
+
+	return huh.NewInput().
+		Title(plain.Title(text.title, description)).
+		Validate(func(s string) error {
+			if s == "" {
+				return nil
+			}
+			_, err := value.setting.Normalize(s)
+			return err
+		}).
+		Value(&value.text)
+}
+
+func getSettingField(value *settingValue, allCommands []Command, overriddenBy string) huh.Field {
+	text := getSettingText(value.setting.Key)
+
+	description := "\n  " + text.description + "\n"
+	if overriddenBy != "" {
+		description += color.HiYellowString("  "+locale.Get("settings.overridden", overriddenBy)) + "\n"
+	}
+
+	if plain.Enabled {
+		return getPlainSettingField(value, text, description)
+	}
+
+	if len(value.setting.Choices) > 0 {
+		field := huh.NewSelect[string]().
+			Title(text.title).
+			Options(huh.NewOptions(value.setting.Choices...)...).
+			Value(&value.text)
+
+		if value.setting.Key == "iconType" {
+			field.DescriptionFunc(func() string {
+				return description + getIconPreview(value.text, allCommands)
+			}, &value.text)
+		} else {
+			field.Description(description)
+		}
+
+		return field
+	}
+
+	if value.setting.Kind == reflect.Bool {
+		field := huh.NewConfirm().
+			Title(text.title).
+			Affirmative(locale.Get("settings.yes")).
+			Negative(locale.Get("settings.no")).
+			Value(&value.enabled)
+
+		if value.setting.Key == "showAllCommands" {
+			field.DescriptionFunc(func() string {
+				return description + getShowAllCommandsPreview(value.enabled, allCommands)
+			}, &value.enabled)
+		} else {
+			field.Description(description)
+		}
+
+		return field
+	}
+
+	return huh.NewInput().
+		Title(text.title).
+		Description(description).
+		Validate(func(s string) error {
+			_, err := value.setting.Normalize(s)
+			return err
+		}).
+		Value(&value.text)
+}
+
+// editSettings shows every setting of the user configuration in one form and
+// saves the changed ones in a single write.
+func editSettings() error {
+	var allCommands []Command
+	if err := json.Unmarshal(commandJSON, &allCommands); err != nil {
+		return err
+	}
+
+	userConfig, configPath, err := config.ReadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[string]string)
+	if configValues, err := config.GetConfigValues(); err == nil {
+		for _, configValue := range configValues {
+			if configValue.Origin != configPath && configValue.Origin != "default" {
+				overrides[configValue.Key] = configValue.Origin
+			}
+		}
+	}
+
+	var values []*settingValue
+	var fields []huh.Field
+
+	for _, setting := range config.GetSettings() {
+		value := &settingValue{setting: setting, original: setting.GetValue(userConfig)}
+		value.text = value.original
+		value.enabled = value.original == "true"
+
+		values = append(values, value)
+		fields = append(fields, getSettingField(value, allCommands, overrides[setting.Key]))
+	}
+
+	err = huh.NewForm(
+		huh.NewGroup(fields...).
+			Title(locale.Get("settings.title")).
+			Description(locale.Get("settings.savedTo", configPath)),
+	).WithTheme(getTheme()).WithAccessible(plain.Enabled).Run()
+
+	if errors.Is(err, huh.ErrUserAborted) {
+		fmt.Println(locale.Get("settings.unchanged"))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var changed []config.ConfigValue
+	for _, value := range values {
+		if plain.Enabled {
+			value.applyPlainAnswer()
+		}
+
+		if value.current() != value.original {
+			changed = append(changed, config.ConfigValue{Key: value.setting.Key, Value: value.current()})
+		}
+	}
+
+	if len(changed) == 0 {
+		fmt.Println(locale.Get("settings.unchanged"))
+		return nil
+	}
+
+	if _, err := config.SetValues(configPath, changed); err != nil {
+		logger.ErrorLogger.Println("Failed to save settings:", err)
+		return err
+	}
+
+	logger.InfoLogger.Printf("Saved %d setting(s) to %s", len(changed), configPath)
+	fmt.Print(locale.Get("settings.saved", color.BlueString(configPath)) + "\n\n")
+
+	effectiveConfig := config.GetConfig()
+	locale.Load(effectiveConfig.Language)
+	if err := theme.Load(effectiveConfig.Theme, config.GetCustomThemePath(effectiveConfig)); err != nil {
+		logger.ErrorLogger.Println("Failed to load theme:", err)
+		utilities.PrintGeneralError(locale.Get("settings.themeFailed", err))
+	}
+
+	return nil
+}
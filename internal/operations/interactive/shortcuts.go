@@ -0,0 +1,221 @@
+package interactive
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/rivo/uniseg"
+)
+
+const noShortcut = "none"
+
+// reservedShortcutKeys move the selection or start filtering in the main
+// menu, a shortcut starting with one of them could never be typed.
+const reservedShortcutKeys = "jkgG/"
+
+// getShortcuts splits the comma separated shortcuts of a command.
+func getShortcuts(command Command) []string {
+	var shortcuts []string
+
+	for _, shortcut := range strings.Split(command.Shortcut, ",") {
+		shortcut = strings.TrimSpace(shortcut)
+		if shortcut != "" && shortcut != noShortcut {
+			shortcuts = append(shortcuts, shortcut)
+		}
+	}
+
+	return shortcuts
+}
+
+// resolveShortcuts applies the shortcuts from the configuration and drops
+// the ones that cannot work. When two shortcuts conflict the entry further
+// up in the menu keeps its shortcut, every dropped one is reported.
+func resolveShortcuts(commands []Command, remapped map[string]string) ([]Command, []string) {
+	var problems []string
+
+	for id, shortcut := range remapped {
+		index := slices.IndexFunc(commands, func(command Command) bool { return command.Id == id })
+		if index == -1 {
+			problems = append(problems, locale.Get("shortcuts.unknownId", id))
+			continue
+		}
+		commands[index].Shortcut = shortcut
+	}
+	slices.Sort(problems)
+
+	owners := make(map[string]Command)
+	var taken []string
+
+	for i, command := range commands {
+		var kept []string
+
+		for _, shortcut := range getShortcuts(command) {
+			if strings.ContainsRune(reservedShortcutKeys, []rune(shortcut)[0]) || strings.ContainsAny(shortcut, " \t") {
+				problems = append(problems, locale.Get("shortcuts.reserved", shortcut, command.Name, strings.Join(strings.Split(reservedShortcutKeys, ""), " ")))
+				continue
+			}
+
+			conflict := slices.IndexFunc(taken, func(other string) bool {
+				return strings.HasPrefix(shortcut, other) || strings.HasPrefix(other, shortcut)
+			})
+			if conflict != -1 {
+				other := taken[conflict]
+				problems = append(problems, locale.Get("shortcuts.conflict", shortcut, command.Name, other, owners[other].Name))
+				continue
+			}
+
+			owners[shortcut] = command
+			taken = append(taken, shortcut)
+			kept = append(kept, shortcut)
+		}
+
+		commands[i].Shortcut = noShortcut
+		if len(kept) > 0 {
+			commands[i].Shortcut = strings.Join(kept, ", ")
+		}
+	}
+
+	return commands, problems
+}
+
+// getOptionTitles lines up the shortcuts behind the titles of the entries.
+func getOptionTitles(commands []Command) []string {
+	width := 0
+	for _, command := range commands {
+		width = max(width, uniseg.StringWidth(getTitle(command)))
+	}
+
+	titles := make([]string, len(commands))
+
+	// accessible mode shows no descriptions and cannot type shortcuts
+	if plain.Enabled {
+		for i, command := range commands {
+			titles[i] = getTitle(command)
+			if command.Description != "" {
+				titles[i] += ": " + command.Description
+			}
+		}
+		return titles
+	}
+
+	for i, command := range commands {
+		title := getTitle(command)
+		titles[i] = " " + title
+
+		if command.Shortcut != noShortcut && command.Shortcut != "" {
+			titles[i] += strings.Repeat(" ", width-uniseg.StringWidth(title)) + "  (" + command.Shortcut + ")"
+		}
+	}
+
+	return titles
+}
+
+// shortcutSelection is the value of the main menu. Once a shortcut was
+// typed it holds the command, the select must not overwrite it with the
+// entry under the cursor while it submits.
+type shortcutSelection struct {
+	value  *Command
+	locked bool
+}
+
+func (s *shortcutSelection) Get() Command {
+	return *s.value
+}
+
+func (s *shortcutSelection) Set(command Command) {
+	if !s.locked {
+		*s.value = command
+	}
+}
+
+// shortcutFilter reads the keys before the main menu gets them. Typed
+// letters are collected while they can still become a shortcut, a complete
+// shortcut selects its command and submits the menu. While the menu is
+// filtered the keys are left alone, Esc outside the filter closes the menu.
+type shortcutFilter struct {
+	commands   []Command
+	selection  *shortcutSelection
+	typed      string
+	filtering  bool
+	filterText string
+}
+
+func (f *shortcutFilter) match(typed string) (Command, bool, bool) {
+	partial := false
+
+	for _, command := range f.commands {
+		for _, shortcut := range getShortcuts(command) {
+			if shortcut == typed {
+				return command, true, false
+			}
+			if strings.HasPrefix(shortcut, typed) {
+				partial = true
+			}
+		}
+	}
+
+	return Command{}, false, partial
+}
+
+func (f *shortcutFilter) filter(_ tea.Model, msg tea.Msg) tea.Msg {
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	if !isKey || f.selection.locked {
+		return msg
+	}
+
+	if f.filtering {
+		switch keyMsg.Type {
+		case tea.KeyEsc, tea.KeyEnter:
+			f.filtering = false
+		case tea.KeyBackspace:
+			if runes := []rune(f.filterText); len(runes) > 0 {
+				f.filterText = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			f.filterText += keyMsg.String()
+		}
+		return msg
+	}
+
+	// the first Esc clears the filter, then it closes the menu
+	if keyMsg.Type == tea.KeyEsc {
+		f.typed = ""
+		if f.filterText != "" {
+			f.filterText = ""
+			return msg
+		}
+		return tea.KeyMsg{Type: tea.KeyCtrlC}
+	}
+
+	if keyMsg.Type != tea.KeyRunes || keyMsg.Alt || keyMsg.Paste {
+		f.typed = ""
+		return msg
+	}
+
+	for _, typed := range []string{f.typed + string(keyMsg.Runes), string(keyMsg.Runes)} {
+		command, found, partial := f.match(typed)
+
+		if found {
+			f.typed = ""
+			*f.selection.value = command
+			f.selection.locked = true
+			return tea.KeyMsg{Type: tea.KeyEnter}
+		}
+
+		if partial {
+			f.typed = typed
+			return nil
+		}
+	}
+
+	f.typed = ""
+
+	if keyMsg.String() == "/" {
+		f.filtering = true
+	}
+
+	return msg
+}
@@ -0,0 +1,96 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/operations/git"
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/config"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+)
+
+// TrustRepository lets the .igitt.yaml of the current repository add
+// commands and shortcuts after showing what they run, remove takes the trust
+// back.
+func TrustRepository(remove bool) error {
+	repoConfigPath := config.GetRepoConfigPath()
+	if repoConfigPath == "" {
+		fmt.Println(color.HiYellowString(locale.Get("trust.noRepoConfig")))
+		return &git.RefusedError{Reason: "there is no .igitt.yaml in this repository"}
+	}
+
+	if remove {
+		removed, err := config.UntrustRepoConfig(repoConfigPath)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			fmt.Println(locale.Get("trust.notTrusted", color.BlueString(repoConfigPath)))
+			return nil
+		}
+		fmt.Println(locale.Get("trust.removed", color.BlueString(repoConfigPath)))
+		return nil
+	}
+
+	repoConfig, err := config.ReadConfigFromPath(repoConfigPath)
+	if err != nil {
+		return err
+	}
+
+	printRepoCommands(repoConfigPath, repoConfig)
+
+	confirmed, err := utilities.Confirm(locale.Get("trust.confirm.title"), locale.Get("trust.confirm.description"))
+	if errors.Is(err, utilities.ErrNoTerminal) {
+		fmt.Println(color.HiRedString(locale.Get("trust.noTerminal")))
+		return &git.RefusedError{Reason: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		fmt.Println(locale.Get("trust.kept"))
+		return &git.RefusedError{Reason: "trusting " + repoConfigPath + " was not confirmed"}
+	}
+
+	if err := config.TrustRepoConfig(repoConfigPath); err != nil {
+		return err
+	}
+
+	fmt.Println(locale.Get("trust.trusted", color.BlueString(repoConfigPath)))
+	return nil
+}
+
+// printRepoCommands shows everything trusting the repository would run.
+func printRepoCommands(repoConfigPath string, repoConfig config.IgittConfig) {
+	fmt.Println(locale.Get("trust.adds", color.BlueString(repoConfigPath)))
+
+	if len(repoConfig.Commands) == 0 && len(repoConfig.Shortcuts) == 0 {
+		fmt.Println("  " + color.HiBlackString(locale.Get("trust.nothing")))
+	}
+
+	for _, custom := range repoConfig.Commands {
+		fmt.Printf("\n  %s (%s)\n", custom.Name, color.CyanString(custom.Id))
+		for _, step := range custom.Steps {
+			if len(step.Git) > 0 {
+				fmt.Println("    git " + strings.Join(step.Git, " "))
+			} else {
+				fmt.Println("    " + color.HiYellowString(locale.Get("trust.shell", step.Shell)))
+			}
+		}
+	}
+
+	if len(repoConfig.Shortcuts) > 0 {
+		fmt.Println("\n  " + locale.Get("trust.shortcuts"))
+		for _, id := range slices.Sorted(maps.Keys(repoConfig.Shortcuts)) {
+			fmt.Printf("    %s: %s\n", color.CyanString(id), repoConfig.Shortcuts[id])
+		}
+	}
+
+	fmt.Println()
+}
@@ -0,0 +1,171 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/operations/git"
+	"github.com/nstr-dev/igitt/internal/utilities/config"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+)
+
+// GetWorkspaceRepositories searches directory for repositories. Without a
+// directory it takes the saved workspace, and the current directory if
+// nothing was saved.
+func GetWorkspaceRepositories(directory string) ([]git.WorkspaceRepository, error) {
+	if directory == "" {
+		workspace, err := config.ReadWorkspace()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(workspace.Repositories) > 0 {
+			return git.GetWorkspaceRepositories(workspace.Repositories), nil
+		}
+
+		directory = "."
+	}
+
+	if info, err := os.Stat(directory); err != nil || !info.IsDir() {
+		fmt.Println(color.HiYellowString(locale.Get("workspace.notADirectory", directory)))
+		return nil, &git.RefusedError{Reason: directory + " is not a directory"}
+	}
+
+	repositories, err := git.DiscoverRepositories(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(repositories) == 0 {
+		fmt.Println(color.HiYellowString(locale.Get("workspace.none", directory)))
+		return nil, &git.RefusedError{Reason: "no repositories found in " + directory}
+	}
+
+	return repositories, nil
+}
+
+// findRepositories returns the root of the repository path is in, or the
+// repositories below path if it is not in one.
+func findRepositories(path string) ([]string, error) {
+	if root, isRepo := git.GetRepositoryRoot(path); isRepo {
+		return []string{filepath.Clean(root)}, nil
+	}
+
+	repositories, err := git.DiscoverRepositories(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []string
+	for _, repository := range repositories {
+		roots = append(roots, repository.Path)
+	}
+
+	return roots, nil
+}
+
+// AddToWorkspace saves the repositories at or below the paths in the
+// workspace.
+func AddToWorkspace(paths []string) error {
+	workspace, err := config.ReadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	added := 0
+
+	for _, path := range paths {
+		roots, err := findRepositories(path)
+		if err != nil {
+			return err
+		}
+
+		if len(roots) == 0 {
+			fmt.Println(color.HiYellowString(locale.Get("workspace.none", path)))
+			return &git.RefusedError{Reason: "no repositories found in " + path}
+		}
+
+		for _, root := range roots {
+			if slices.Contains(workspace.Repositories, root) {
+				continue
+			}
+			workspace.Repositories = append(workspace.Repositories, root)
+			fmt.Println(locale.Get("workspace.added", color.BlueString(root)))
+			added++
+		}
+	}
+
+	if added == 0 {
+		fmt.Println(locale.Get("workspace.nothingAdded"))
+		return nil
+	}
+
+	logger.InfoLogger.Printf("Added %d repositories to the workspace", added)
+	return config.SaveWorkspace(workspace)
+}
+
+// RemoveFromWorkspace drops the paths from the saved workspace. They do not
+// have to exist anymore, so they are compared as given. Nothing is removed if
+// one of them is not saved.
+func RemoveFromWorkspace(paths []string) error {
+	workspace, err := config.ReadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	var absolutePaths, notSaved []string
+	for _, path := range paths {
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(workspace.Repositories, absolute) {
+			fmt.Println(color.HiYellowString(locale.Get("workspace.notSaved", absolute)))
+			notSaved = append(notSaved, absolute)
+		}
+		absolutePaths = append(absolutePaths, absolute)
+	}
+
+	if len(notSaved) > 0 {
+		return &git.RefusedError{Reason: "not in the workspace: " + strings.Join(notSaved, ", ")}
+	}
+
+	for _, absolute := range absolutePaths {
+		index := slices.Index(workspace.Repositories, absolute)
+		if index == -1 {
+			// given twice
+			continue
+		}
+
+		workspace.Repositories = slices.Delete(workspace.Repositories, index, index+1)
+		fmt.Println(locale.Get("workspace.removed", color.BlueString(absolute)))
+	}
+
+	return config.SaveWorkspace(workspace)
+}
+
+// PrintWorkspace lists the saved repositories.
+func PrintWorkspace() error {
+	workspace, err := config.ReadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	if len(workspace.Repositories) == 0 {
+		fmt.Println(locale.Get("workspace.empty", color.CyanString("igitt workspace add")))
+		return nil
+	}
+
+	fmt.Println(color.HiBlackString(locale.Get("workspace.savedIn", config.GetWorkspacePath())))
+	for _, repository := range workspace.Repositories {
+		fmt.Println(repository)
+	}
+
+	return nil
+}
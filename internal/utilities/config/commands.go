@@ -0,0 +1,213 @@
+package config
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"gopkg.in/yaml.v3"
+)
+
+// CustomCommand is a menu entry defined in the configuration. It runs its
+// steps in order after asking for the values of its prompts.
+type CustomCommand struct {
+	Id              string          `yaml:"id"`
+	Name            string          `yaml:"name"`
+	Description     string          `yaml:"description"`
+	Icon            string          `yaml:"icon"`
+	IconEmoji       string          `yaml:"iconEmoji"`
+	IconNerdFont    string          `yaml:"iconNerdfont"`
+	IconAscii       string          `yaml:"iconAscii"`
+	Shortcut        string          `yaml:"shortcut"`
+	InsideRepoOnly  bool            `yaml:"insideRepoOnly"`
+	OutsideRepoOnly bool            `yaml:"outsideRepoOnly"`
+	Prompts         []CommandPrompt `yaml:"prompts"`
+	Steps           []CommandStep   `yaml:"steps"`
+}
+
+// CommandPrompt asks for a parameter. Git steps use its value as {{name}},
+// shell steps get it as the environment variable IGITT_<NAME>.
+type CommandPrompt struct {
+	Name        string   `yaml:"name"`
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Default     string   `yaml:"default"`
+	Suggestions []string `yaml:"suggestions"`
+}
+
+// CommandStep is either a git command given as its arguments or a line for
+// the shell.
+type CommandStep struct {
+	Description string   `yaml:"description"`
+	Git         []string `yaml:"git"`
+	Shell       string   `yaml:"shell"`
+}
+
+const customCommandsExample = `For example:
+commands:
+  - id: "update-from-main"
+    name: "Update from main"
+    description: "Rebase the current branch onto origin/main and push it"
+    icon: "⟳"
+    insideRepoOnly: true
+    steps:
+      - git: ["fetch", "origin"]
+      - git: ["rebase", "origin/main"]
+      - git: ["push", "--force-with-lease"]
+  - id: "new-feature"
+    name: "New feature"
+    prompts:
+      - name: "feature"
+        title: "Feature name"
+    steps:
+      - git: ["checkout", "-b", "feature/{{feature}}"]
+      - shell: "echo Started $IGITT_FEATURE"`
+
+var promptNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// FillParameters replaces the {{name}} placeholders of a git step.
+func (s CommandStep) FillParameters(parameters map[string]string) []string {
+	var arguments []string
+
+	for _, argument := range s.Git {
+		for name, value := range parameters {
+			argument = strings.ReplaceAll(argument, "{{"+name+"}}", value)
+		}
+		arguments = append(arguments, argument)
+	}
+
+	return arguments
+}
+
+func GetParameterEnvironment(parameters map[string]string) []string {
+	var environment []string
+
+	for name, value := range parameters {
+		environment = append(environment, "IGITT_"+strings.ToUpper(name)+"="+value)
+	}
+
+	return environment
+}
+
+// mergeCustomCommands adds the repository's commands to the user's. An
+// entry with the same id replaces the user's one.
+func mergeCustomCommands(userCommands []CustomCommand, repoCommands []CustomCommand) []CustomCommand {
+	merged := append([]CustomCommand{}, userCommands...)
+
+	for _, repoCommand := range repoCommands {
+		replaced := false
+		for i := range merged {
+			if merged[i].Id == repoCommand.Id {
+				merged[i] = repoCommand
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, repoCommand)
+		}
+	}
+
+	return merged
+}
+
+func getYamlKeys(value interface{}) map[string]bool {
+	keys := make(map[string]bool)
+
+	valueType := reflect.TypeOf(value)
+	for i := 0; i < valueType.NumField(); i++ {
+		key, _, _ := strings.Cut(valueType.Field(i).Tag.Get("yaml"), ",")
+		keys[key] = true
+	}
+
+	return keys
+}
+
+func getMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func checkKeys(mapping *yaml.Node, allowed map[string]bool, context string) []ValidationProblem {
+	var problems []ValidationProblem
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if !allowed[mapping.Content[i].Value] {
+			problems = append(problems, ValidationProblem{mapping.Content[i].Line, locale.Get("commands.unknownKey."+context, mapping.Content[i].Value)})
+		}
+	}
+
+	return problems
+}
+
+func validateCustomCommands(node *yaml.Node) []ValidationProblem {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+
+	if node.Kind != yaml.SequenceNode {
+		return []ValidationProblem{{node.Line, locale.Get("commands.notList")}}
+	}
+
+	var problems []ValidationProblem
+	seenIds := make(map[string]int)
+
+	for _, commandNode := range node.Content {
+		if commandNode.Kind != yaml.MappingNode {
+			problems = append(problems, ValidationProblem{commandNode.Line, locale.Get("commands.notMapping")})
+			continue
+		}
+
+		problems = append(problems, checkKeys(commandNode, getYamlKeys(CustomCommand{}), "command")...)
+
+		var command CustomCommand
+		if err := commandNode.Decode(&command); err != nil {
+			problems = append(problems, ValidationProblem{commandNode.Line, err.Error()})
+			continue
+		}
+
+		if command.Id == "" || command.Name == "" {
+			problems = append(problems, ValidationProblem{commandNode.Line, locale.Get("commands.noId")})
+		}
+		if line, duplicate := seenIds[command.Id]; duplicate && command.Id != "" {
+			problems = append(problems, ValidationProblem{commandNode.Line, locale.Get("commands.duplicateId", command.Id, line)})
+		}
+		seenIds[command.Id] = commandNode.Line
+
+		if command.InsideRepoOnly && command.OutsideRepoOnly {
+			problems = append(problems, ValidationProblem{commandNode.Line, locale.Get("commands.insideAndOutside")})
+		}
+
+		if promptsNode := getMappingValue(commandNode, "prompts"); promptsNode != nil {
+			for _, promptNode := range promptsNode.Content {
+				problems = append(problems, checkKeys(promptNode, getYamlKeys(CommandPrompt{}), "prompt")...)
+			}
+		}
+		for i, prompt := range command.Prompts {
+			if !promptNamePattern.MatchString(prompt.Name) {
+				problems = append(problems, ValidationProblem{getMappingValue(commandNode, "prompts").Content[i].Line, locale.Get("commands.invalidPrompt", prompt.Name)})
+			}
+		}
+
+		stepsNode := getMappingValue(commandNode, "steps")
+		if stepsNode == nil || len(command.Steps) == 0 {
+			problems = append(problems, ValidationProblem{commandNode.Line, locale.Get("commands.noSteps", command.Name)})
+			continue
+		}
+
+		for i, step := range command.Steps {
+			stepNode := stepsNode.Content[i]
+			problems = append(problems, checkKeys(stepNode, getYamlKeys(CommandStep{}), "step")...)
+
+			if (len(step.Git) == 0) == (step.Shell == "") {
+				problems = append(problems, ValidationProblem{stepNode.Line, locale.Get("commands.gitOrShell")})
+			}
+		}
+	}
+
+	return problems
+}
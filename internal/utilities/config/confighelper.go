@@ -1,30 +1,64 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
 	"gopkg.in/yaml.v3"
 )
 
-const configFileName = "igittconfig.yaml"
+const configFileName = "config.yaml"
+const legacyConfigFileName = "igittconfig.yaml"
+const repoConfigFileName = ".igitt.yaml"
+const configEnvVariable = "IGITT_CONFIG"
+const pluginsDirName = "plugins"
 
+// IgittConfig is the single source for the settings: the default file,
+// validation and the settings editor are all generated from these tags.
 type IgittConfig struct {
-	IconType        string `yaml:"iconType"`
-	ShowAllCommands bool   `yaml:"showAllCommands"`
+	SchemaVersion   int               `yaml:"schemaVersion" default:"2" internal:"true" comment:"The layout version of this file. Igitt updates it automatically, please do not change it."`
+	IconType        string            `yaml:"iconType" default:"unicode" choices:"emoji,unicode,nerdfont,ascii" comment:"How icons are displayed in the interactive mode."`
+	ShowAllCommands bool              `yaml:"showAllCommands" default:"false" comment:"Show all commands in the interactive mode, even if not in a Git repository."`
+	ShowDashboard   bool              `yaml:"showDashboard" default:"true" comment:"Show the branch, its upstream, the changes, stashes and last commit of the repository above the menu of the interactive mode."`
+	StayInMenu      bool              `yaml:"stayInMenu" default:"false" comment:"Return to the menu of the interactive mode after each operation instead of exiting. Esc or the Exit entry end the session."`
+	ExplainCommands bool              `yaml:"explainCommands" default:"false" comment:"Print the git command behind every operation with a short explanation, the same as always passing --explain."`
+	RawGitErrors    bool              `yaml:"rawGitErrors" default:"false" comment:"Show Git's original message below the explanation of a recognized error, the same as always passing --raw-errors."`
+	Plain           bool              `yaml:"plain" default:"false" comment:"Print plain text without colors, spinners or decorative glyphs and ask one question per line, for screen readers and logs. The same as always passing --plain."`
+	Language        string            `yaml:"language" default:"auto" comment:"The language of the texts igitt shows. \"auto\" follows the LANG environment variable, languages without a translation fall back to English."`
+	Theme           string            `yaml:"theme" default:"catppuccin" choices:"catppuccin,charm,dracula,base16,base,custom" comment:"The colors of the interactive mode. Choose \"custom\" to use the palette in customThemeFile."`
+	CustomThemeFile string            `yaml:"customThemeFile" default:"theme.yaml" comment:"A YAML file with a custom color palette, relative to this file. Only used if theme is \"custom\"."`
+	WorkspaceJobs   int               `yaml:"workspaceJobs" default:"8" comment:"How many repositories igitt workspace works on at the same time."`
+	Commands        []CustomCommand   `yaml:"commands" default:"[]" comment:"Your own entries for the interactive menu. Each one asks for its prompts and then runs its steps in order, stopping at the first that fails. Git steps use a prompt as {{name}}, shell steps as $IGITT_NAME."`
+	Shortcuts       map[string]string `yaml:"shortcuts" default:"{}" comment:"Change the shortcuts of the interactive menu. Typing a shortcut runs its entry right away. Keyed by the id of the entry, several shortcuts are separated by commas and \"none\" removes them."`
 }
 
 func InitialConfig() (bool, error) {
 	configExists, configPath := HasConfigFile()
 
 	if configExists {
+		if err := MigrateConfig(configPath); err != nil {
+			logger.ErrorLogger.Println("Failed to migrate config:", err)
+			utilities.PrintGeneralError(locale.Get("config.updateFailed", configPath, err))
+		}
 		return false, nil
 	}
 
+	err := os.MkdirAll(filepath.Dir(configPath), 0755)
+
+	if err != nil {
+		logger.ErrorLogger.Println("Failed to create config directory:", err)
+		return false, err
+	}
+
 	file, err := os.OpenFile(configPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 
 	if err != nil {
@@ -44,35 +78,103 @@ func InitialConfig() (bool, error) {
 	return true, nil
 }
 
-func HasConfigFile() (bool, string) {
-	executable, err := os.Executable()
-	executableDir := filepath.Dir(executable)
-	configPath := filepath.Join(executableDir, configFileName)
+// https://specifications.freedesktop.org/basedir-spec/latest/
 
+func getLegacyConfigPath() string {
+	executable, err := os.Executable()
 	if err != nil {
 		logger.ErrorLogger.Panic(err)
 	}
 
-	_, err = os.Stat(configPath)
-	fileExists := !os.IsNotExist(err)
-	isFileEmpty := true
+	return filepath.Join(filepath.Dir(executable), legacyConfigFileName)
+}
+
+func getXdgConfigPath() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
 
-	if fileExists {
-		file, err := os.ReadFile(configPath)
+	if configHome == "" {
+		var err error
+		configHome, err = os.UserConfigDir()
 		if err != nil {
 			logger.ErrorLogger.Panic(err)
 		}
-		fileContents := string(file)
-		isFileEmpty = len(fileContents) == 0
 	}
 
-	result := fileExists && !isFileEmpty
+	return filepath.Join(configHome, "igitt", configFileName)
+}
 
-	return result, configPath
+func isNonEmptyFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.Size() > 0
+}
+
+// resolveConfigPath picks the user configuration in the order $IGITT_CONFIG,
+// $XDG_CONFIG_HOME/igitt/config.yaml and the legacy file next to the
+// executable. New configurations are created at the XDG location.
+func resolveConfigPath() string {
+	if path := os.Getenv(configEnvVariable); path != "" {
+		return path
+	}
+
+	xdgConfigPath := getXdgConfigPath()
+	if isNonEmptyFile(xdgConfigPath) {
+		return xdgConfigPath
+	}
+
+	legacyConfigPath := getLegacyConfigPath()
+	if isNonEmptyFile(legacyConfigPath) {
+		logger.InfoLogger.Println("Using legacy config file next to the executable:", legacyConfigPath)
+		return legacyConfigPath
+	}
+
+	return xdgConfigPath
+}
+
+func HasConfigFile() (bool, string) {
+	configPath := resolveConfigPath()
+
+	return isNonEmptyFile(configPath), configPath
+}
+
+func GetRepoConfigFile(repoRoot string) string {
+	return filepath.Join(repoRoot, repoConfigFileName)
+}
+
+// GetPluginsDir returns the plugins directory next to the user
+// configuration. It does not create the configuration, plugins are looked up
+// before the command runs.
+func GetPluginsDir() string {
+	return filepath.Join(filepath.Dir(resolveConfigPath()), pluginsDirName)
+}
+
+// GetCustomThemePath resolves customThemeFile relative to the directory of
+// the user configuration.
+func GetCustomThemePath(config IgittConfig) string {
+	if filepath.IsAbs(config.CustomThemeFile) {
+		return config.CustomThemeFile
+	}
+
+	return filepath.Join(filepath.Dir(GetConfigPath(false)), config.CustomThemeFile)
+}
+
+// GetRepoConfigPath returns the path of the repository's .igitt.yaml if the
+// current directory is inside a repository that has one.
+func GetRepoConfigPath() string {
+	repoRoot := utilities.GetRepoRoot()
+	if repoRoot == "" {
+		return ""
+	}
+
+	repoConfigPath := GetRepoConfigFile(repoRoot)
+	if !isNonEmptyFile(repoConfigPath) {
+		return ""
+	}
+
+	return repoConfigPath
 }
 
 func GetConfig() IgittConfig {
-	configExists, configPath := HasConfigFile()
+	configExists, _ := HasConfigFile()
 	if !configExists {
 		logger.ErrorLogger.Print("Config file does not exist, creating one now")
 
@@ -84,17 +186,142 @@ func GetConfig() IgittConfig {
 		}
 	}
 
-	config, err := ReadConfigFromPath(configPath)
+	config, err := LoadConfig()
+
+	var invalidConfig *InvalidConfigError
+	if errors.As(err, &invalidConfig) {
+		exitWithInvalidConfig(invalidConfig.Path, invalidConfig.Err, invalidConfig.advice)
+	}
+
+	return config
+}
+
+// InvalidConfigError is returned by LoadConfig for a configuration file
+// that cannot be read.
+type InvalidConfigError struct {
+	Path   string
+	Err    error
+	advice string
+}
+
+func (e *InvalidConfigError) Error() string {
+	return locale.Get("config.readFailed", e.Path, e.Err)
+}
+
+func (e *InvalidConfigError) Unwrap() error {
+	return e.Err
+}
+
+// LoadConfig reads the settings like GetConfig, but returns an invalid file
+// as an error instead of exiting, for the commands that help fixing it. The
+// settings that could not be read keep their defaults.
+func LoadConfig() (IgittConfig, error) {
+	configPath := resolveConfigPath()
+
+	config, err := getDefaults()
 	if err != nil {
-		utilities.PrintGeneralError(fmt.Sprintf("Failed to read the configuration at:\n%s.\n\nYou can either fix it or delete it to generate a new configuration file.", configPath))
-		logger.ErrorLogger.Fatal("Failed to read the configuration. Perhaps it is invalid?")
+		return config, err
+	}
+
+	if isNonEmptyFile(configPath) {
+		config, err = mergeConfigFromPath(config, configPath)
+		if err != nil {
+			return config, &InvalidConfigError{Path: configPath, Err: err, advice: locale.Get("config.invalid.advice")}
+		}
+	}
+
+	repoConfigPath := GetRepoConfigPath()
+	if repoConfigPath != "" {
+		userCommands := config.Commands
+		// the repository's shortcuts are decoded into the same map
+		userShortcuts := maps.Clone(config.Shortcuts)
+
+		config, err = mergeConfigFromPath(config, repoConfigPath)
+		if err != nil {
+			return config, &InvalidConfigError{Path: repoConfigPath, Err: err, advice: locale.Get("config.invalid.repoAdvice")}
+		}
+
+		if !isRepoConfigTrusted(repoConfigPath) {
+			// a cloned repository must not run its own commands, not even
+			// behind the shortcuts the user is used to
+			config.Commands = userCommands
+			config.Shortcuts = userShortcuts
+		} else {
+			// a list would replace the user's one, commands are combined
+			// instead
+			config.Commands = mergeCustomCommands(userCommands, config.Commands)
+		}
+	}
+
+	return config, nil
+}
+
+// loadConfigOrWarn reads the settings like LoadConfig for the commands that
+// only show them. An invalid file is reported on stderr and the settings
+// that could not be read keep their defaults.
+func loadConfigOrWarn() IgittConfig {
+	config, err := LoadConfig()
+
+	var invalidConfig *InvalidConfigError
+	if errors.As(err, &invalidConfig) {
+		fmt.Fprintln(os.Stderr, color.HiYellowString(locale.Get("config.showingDefaults", invalidConfig.Path)))
+	}
+	if err != nil {
+		logger.WarningLogger.Println("Showing the settings with the defaults:", err)
 	}
 
 	return config
 }
 
+func exitWithInvalidConfig(configPath string, readErr error, advice string) {
+	problems, err := ValidateConfigFile(configPath)
+	if err != nil || len(problems) == 0 {
+		problems = []ValidationProblem{{Message: readErr.Error()}}
+	}
+
+	var details []string
+	for _, problem := range problems {
+		details = append(details, "  "+problem.String())
+	}
+
+	utilities.PrintGeneralError(locale.Get("config.invalid", configPath, strings.Join(details, "\n"), advice))
+	logger.ErrorLogger.Fatalf("Failed to read the configuration at %s: %v", configPath, readErr)
+}
+
+func getDefaults() (IgittConfig, error) {
+	var defaults IgittConfig
+	err := yaml.Unmarshal([]byte(GetDefaultConfig()), &defaults)
+	return defaults, err
+}
+
+// ReadUserConfig returns the settings of the user configuration file alone,
+// without the overrides of a repository, falling back to the defaults.
+func ReadUserConfig() (IgittConfig, string, error) {
+	configPath := GetConfigPath(false)
+
+	defaults, err := getDefaults()
+	if err != nil {
+		return IgittConfig{}, configPath, err
+	}
+
+	config, err := mergeConfigFromPath(defaults, configPath)
+	return config, configPath, err
+}
+
+// ReadConfigFromPath reads a configuration file, keys it leaves out keep
+// their defaults.
 func ReadConfigFromPath(configPath string) (IgittConfig, error) {
-	var config IgittConfig
+	defaults, err := getDefaults()
+	if err != nil {
+		return IgittConfig{}, err
+	}
+
+	return mergeConfigFromPath(defaults, configPath)
+}
+
+// mergeConfigFromPath decodes the file on top of config, so only the keys
+// set in the file replace existing values.
+func mergeConfigFromPath(config IgittConfig, configPath string) (IgittConfig, error) {
 	file, err := os.Open(configPath)
 	if err != nil {
 		return config, err
@@ -103,22 +330,83 @@ func ReadConfigFromPath(configPath string) (IgittConfig, error) {
 
 	decoder := yaml.NewDecoder(file)
 	err = decoder.Decode(&config)
+	if err == io.EOF {
+		return config, nil
+	}
 	return config, err
 }
 
-func GetDefaultConfig() string {
-	configContent := `# This is the configuration for Igitt.
+const defaultConfigHeader = `# This is the configuration for Igitt.
 # Please adjust the values as needed.
+`
 
-# Choices: "emoji", "unicode", "nerdfont", "ascii" - Default: "unicode"
-iconType: "unicode"
+func GetDefaultConfig() string {
+	var configContent strings.Builder
+	configContent.WriteString(defaultConfigHeader)
 
-# Show all commands in the interactive mode, even if not in a Git repository.
-# Default: false
-showAllCommands: false
-`
+	for _, setting := range getAllSettings() {
+		configContent.WriteString("\n" + getDefaultEntry(setting))
+	}
+
+	return configContent.String()
+}
+
+// settingExamples are shown for the settings that are hard to describe in a
+// single comment.
+var settingExamples = map[string]string{
+	"commands":  customCommandsExample,
+	"shortcuts": shortcutsExample,
+}
+
+// getDefaultEntry renders a setting with its documentation as it appears in
+// a newly created configuration file.
+func getDefaultEntry(setting Setting) string {
+	var entry strings.Builder
+
+	for _, line := range wrapComment(setting.Comment, 76) {
+		entry.WriteString("# " + line + "\n")
+	}
+
+	if example, found := settingExamples[setting.Key]; found {
+		entry.WriteString("#\n")
+		for _, line := range strings.Split(example, "\n") {
+			entry.WriteString("# " + line + "\n")
+		}
+	}
 
-	return configContent
+	if !setting.Internal {
+		if len(setting.Choices) > 0 {
+			entry.WriteString(fmt.Sprintf("# Choices: \"%s\" - Default: %s\n", strings.Join(setting.Choices, "\", \""), setting.formatDefault()))
+		} else {
+			entry.WriteString(fmt.Sprintf("# Default: %s\n", setting.formatDefault()))
+		}
+	}
+
+	entry.WriteString(fmt.Sprintf("%s: %s\n", setting.Key, setting.formatDefault()))
+
+	return entry.String()
+}
+
+func wrapComment(comment string, width int) []string {
+	var lines []string
+	var line string
+
+	for _, word := range strings.Fields(comment) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return lines
 }
 
 func GetConfigPath(print bool) string {
@@ -134,9 +422,15 @@ func GetConfigPath(print bool) string {
 	}
 
 	if print {
-		fmt.Printf("\n\nTo edit the configuration, %s in your text editor:\n\n", color.YellowString("open the following file"))
+		fmt.Print("\n\n" + locale.Get("config.editHint", color.YellowString(locale.Get("config.editHint.open"))) + "\n\n")
 		color.Blue(configPath)
 		fmt.Println()
+
+		if repoConfigPath := GetRepoConfigPath(); repoConfigPath != "" {
+			fmt.Print(locale.Get("config.repoOverrides", color.YellowString(repoConfigFileName)) + "\n\n")
+			color.Blue(repoConfigPath)
+			fmt.Println()
+		}
 	}
 
 	return configPath
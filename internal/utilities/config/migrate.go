@@ -0,0 +1,217 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"gopkg.in/yaml.v3"
+)
+
+const schemaVersionKey = "schemaVersion"
+
+// Files written before schemaVersion existed are version 1.
+const unversionedSchemaVersion = 1
+
+type migration struct {
+	version     int
+	description string
+	apply       func(content []byte) ([]byte, error)
+}
+
+// migrations upgrade a file to the version in their version field. They
+// edit the text of the file so the user's comments and layout survive. Keys
+// missing from the file are added afterwards for every upgrade, so steps
+// only need to handle renamed keys and changed values. Files of the current
+// version are not touched, keys the user removed take their default.
+var migrations = []migration{
+	{
+		version:     2,
+		description: "Write the icon type in lower case",
+		apply: func(content []byte) ([]byte, error) {
+			value, found := readScalar(content, "iconType")
+			if !found || value == strings.ToLower(value) {
+				return content, nil
+			}
+
+			setting, _ := getAnySetting("iconType")
+			return applyValue(content, setting, strings.ToLower(value))
+		},
+	},
+}
+
+func getCurrentSchemaVersion() int {
+	setting, _ := getAnySetting(schemaVersionKey)
+	version, err := strconv.Atoi(setting.Default)
+	if err != nil {
+		logger.ErrorLogger.Panic("Invalid default schema version:", err)
+	}
+	return version
+}
+
+func readScalar(content []byte, key string) (string, bool) {
+	document, err := readConfigDocument(content)
+	if err != nil {
+		return "", false
+	}
+
+	mapping := document.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key && mapping.Content[i+1].Kind == yaml.ScalarNode {
+			return mapping.Content[i+1].Value, true
+		}
+	}
+
+	return "", false
+}
+
+func getSchemaVersion(content []byte) int {
+	value, found := readScalar(content, schemaVersionKey)
+	if !found {
+		return unversionedSchemaVersion
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return unversionedSchemaVersion
+	}
+
+	return version
+}
+
+func addMissingKeys(content []byte) ([]byte, []string, error) {
+	document, err := readConfigDocument(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	present := make(map[string]bool)
+	mapping := document.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		present[mapping.Content[i].Value] = true
+	}
+
+	var added []string
+
+	for _, setting := range getAllSettings() {
+		if present[setting.Key] {
+			continue
+		}
+
+		if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+			content = append(content, '\n')
+		}
+		content = append(content, "\n"+getDefaultEntry(setting)...)
+		added = append(added, setting.Key)
+	}
+
+	return content, added, nil
+}
+
+// getBackupPath returns a path for the copy of the file before migrating
+// it from version. An existing backup is never replaced, e.g. when the user
+// restored an old file, the next free number is used instead.
+func getBackupPath(configPath string, version int) string {
+	backupPath := fmt.Sprintf("%s.v%d.bak", configPath, version)
+
+	for number := 1; ; number++ {
+		if _, err := os.Lstat(backupPath); os.IsNotExist(err) {
+			return backupPath
+		}
+		backupPath = fmt.Sprintf("%s.v%d.%d.bak", configPath, version, number)
+	}
+}
+
+// MigrateConfig upgrades a configuration file of an older schema and keeps a
+// copy of the previous file next to it. Files of the current schema, files
+// with problems and read-only files are left as they are.
+func MigrateConfig(configPath string) error {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	version := getSchemaVersion(content)
+	currentVersion := getCurrentSchemaVersion()
+
+	if version > currentVersion {
+		logger.WarningLogger.Printf("Config file %s has schema version %d, this igitt only knows version %d", configPath, version, currentVersion)
+		return nil
+	}
+
+	if version == currentVersion {
+		return nil
+	}
+
+	// invalid files are reported with line numbers when they are read or
+	// validated, rewriting them first would only move the lines
+	problems, err := ValidateConfigFile(configPath)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		logger.WarningLogger.Printf("Not migrating config file %s, it has %d problem(s)", configPath, len(problems))
+		return nil
+	}
+
+	if err := checkWritable(configPath); err != nil {
+		logger.InfoLogger.Println("Not migrating config file:", err)
+		return nil
+	}
+
+	migrated := content
+	for _, step := range migrations {
+		if step.version <= version {
+			continue
+		}
+
+		logger.InfoLogger.Printf("Migrating config to schema version %d: %s", step.version, step.description)
+		migrated, err = step.apply(migrated)
+		if err != nil {
+			return fmt.Errorf("%s: %w", locale.Get("config.migrateFailed", step.version), err)
+		}
+	}
+
+	migrated, added, err := addMissingKeys(migrated)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(added, schemaVersionKey) {
+		setting, _ := getAnySetting(schemaVersionKey)
+		migrated, err = applyValue(migrated, setting, strconv.Itoa(currentVersion))
+		if err != nil {
+			return err
+		}
+	}
+
+	if string(migrated) == string(content) {
+		return nil
+	}
+
+	backupPath := getBackupPath(configPath, version)
+	backup, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err == nil {
+		_, err = backup.Write(content)
+		if closeErr := backup.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", locale.Get("config.backupFailed"), err)
+	}
+
+	if err := writeConfigFile(configPath, migrated); err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Migrated config %s from schema version %d to %d, added %v, backup at %s", configPath, version, currentVersion, added, backupPath)
+	fmt.Println(locale.Get("config.migrated", color.BlueString(backupPath)) + "\n")
+
+	return nil
+}
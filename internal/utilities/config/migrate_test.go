@@ -0,0 +1,247 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func getSettingForTest(t *testing.T, key string) Setting {
+	t.Helper()
+
+	setting, found := getAnySetting(key)
+	if !found {
+		t.Fatalf("setting %s does not exist", key)
+	}
+	return setting
+}
+
+func writeFileForTest(t *testing.T, content string) string {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return configPath
+}
+
+func readFileForTest(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestAddMissingKeys(t *testing.T) {
+	var allKeys []string
+	for _, setting := range getAllSettings() {
+		allKeys = append(allKeys, setting.Key)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		added   []string
+	}{
+		{"empty file", "", allKeys},
+		{"complete file", GetDefaultConfig(), nil},
+		{"one key set", "iconType: \"ascii\" # mine\n", slices.DeleteFunc(slices.Clone(allKeys), func(key string) bool { return key == "iconType" })},
+		{"no trailing newline", "iconType: ascii", slices.DeleteFunc(slices.Clone(allKeys), func(key string) bool { return key == "iconType" })},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, added, err := addMissingKeys([]byte(test.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(added, test.added) {
+				t.Errorf("added %v, want %v", added, test.added)
+			}
+
+			if !strings.HasPrefix(string(content), test.content) {
+				t.Errorf("the existing content was changed:\n%s", content)
+			}
+
+			// the result must hold every key exactly once
+			document, err := readConfigDocument(content)
+			if err != nil {
+				t.Fatalf("result is not valid YAML: %v\n%s", err, content)
+			}
+			var keys []string
+			mapping := document.Content[0]
+			for i := 0; i+1 < len(mapping.Content); i += 2 {
+				keys = append(keys, mapping.Content[i].Value)
+			}
+			slices.Sort(keys)
+			want := slices.Sorted(slices.Values(allKeys))
+			if !slices.Equal(keys, want) {
+				t.Errorf("keys %v, want %v", keys, want)
+			}
+		})
+	}
+
+	if _, _, err := addMissingKeys([]byte("- a list\n")); err == nil {
+		t.Error("a list should not be accepted as configuration")
+	}
+}
+
+func TestApplyValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		value   string
+		want    string
+	}{
+		{
+			name:    "replaces the value and keeps the comments",
+			content: "# icons\niconType: \"unicode\" # mine\n\nshowDashboard: true\n",
+			key:     "iconType",
+			value:   "ascii",
+			want:    "# icons\niconType: \"ascii\" # mine\n\nshowDashboard: true\n",
+		},
+		{
+			name:    "replaces a boolean",
+			content: "showDashboard: true\n",
+			key:     "showDashboard",
+			value:   "false",
+			want:    "showDashboard: false\n",
+		},
+		{
+			name:    "appends a missing string quoted",
+			content: "showDashboard: true\n",
+			key:     "theme",
+			value:   "dracula",
+			want:    "showDashboard: true\ntheme: \"dracula\"\n",
+		},
+		{
+			name:    "appends after a missing newline",
+			content: "showDashboard: true",
+			key:     "workspaceJobs",
+			value:   "4",
+			want:    "showDashboard: true\nworkspaceJobs: 4\n",
+		},
+		{
+			name:    "appends to an empty file",
+			content: "",
+			key:     "stayInMenu",
+			value:   "true",
+			want:    "stayInMenu: true\n",
+		},
+		{
+			name:    "replaces a nested value with a scalar",
+			content: "showAllCommands: [1, 2]\n",
+			key:     "showAllCommands",
+			value:   "true",
+			want:    "showAllCommands: true\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := applyValue([]byte(test.content), getSettingForTest(t, test.key), test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("applyValue() =\n%q\nwant\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMigrateConfig(t *testing.T) {
+	t.Run("upgrades an old file and keeps a backup", func(t *testing.T) {
+		original := "iconType: Nerdfont # mine\n"
+		configPath := writeFileForTest(t, original)
+
+		if err := MigrateConfig(configPath); err != nil {
+			t.Fatal(err)
+		}
+
+		migrated := readFileForTest(t, configPath)
+		if !strings.HasPrefix(migrated, "iconType: nerdfont # mine\n") {
+			t.Errorf("the icon type was not migrated:\n%s", migrated)
+		}
+		if getSchemaVersion([]byte(migrated)) != getCurrentSchemaVersion() {
+			t.Errorf("the schema version was not updated:\n%s", migrated)
+		}
+		if backup := readFileForTest(t, configPath+".v1.bak"); backup != original {
+			t.Errorf("backup = %q, want %q", backup, original)
+		}
+	})
+
+	t.Run("leaves files of the current version alone", func(t *testing.T) {
+		original := "schemaVersion: 2\niconType: \"ascii\"\n"
+		configPath := writeFileForTest(t, original)
+
+		if err := MigrateConfig(configPath); err != nil {
+			t.Fatal(err)
+		}
+
+		if content := readFileForTest(t, configPath); content != original {
+			t.Errorf("the file was changed:\n%s", content)
+		}
+		if _, err := os.Stat(configPath + ".v2.bak"); !os.IsNotExist(err) {
+			t.Error("a backup was written")
+		}
+	})
+
+	t.Run("leaves invalid files alone", func(t *testing.T) {
+		original := "showAllCommands: [1, 2]\nunknownKey: true\n"
+		configPath := writeFileForTest(t, original)
+
+		if err := MigrateConfig(configPath); err != nil {
+			t.Fatal(err)
+		}
+
+		if content := readFileForTest(t, configPath); content != original {
+			t.Errorf("the file was changed:\n%s", content)
+		}
+		if _, err := os.Stat(configPath + ".v1.bak"); !os.IsNotExist(err) {
+			t.Error("a backup was written")
+		}
+	})
+
+	t.Run("leaves read-only files alone", func(t *testing.T) {
+		original := "iconType: \"ascii\"\n"
+		configPath := writeFileForTest(t, original)
+		if err := os.Chmod(configPath, 0444); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := MigrateConfig(configPath); err != nil {
+			t.Fatal(err)
+		}
+
+		if content := readFileForTest(t, configPath); content != original {
+			t.Errorf("the file was changed:\n%s", content)
+		}
+	})
+
+	t.Run("does not replace an existing backup", func(t *testing.T) {
+		configPath := writeFileForTest(t, "iconType: \"ascii\"\n")
+		if err := os.WriteFile(configPath+".v1.bak", []byte("older"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := MigrateConfig(configPath); err != nil {
+			t.Fatal(err)
+		}
+
+		if backup := readFileForTest(t, configPath+".v1.bak"); backup != "older" {
+			t.Errorf("the existing backup was replaced with %q", backup)
+		}
+		if backup := readFileForTest(t, configPath+".v1.1.bak"); backup != "iconType: \"ascii\"\n" {
+			t.Errorf("the new backup holds %q", backup)
+		}
+	})
+}
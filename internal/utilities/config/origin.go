@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultOrigin = "default"
+
+type ConfigValue struct {
+	Key    string
+	Value  string
+	Origin string
+}
+
+// readConfigKeys returns the top-level keys set in a config file.
+func readConfigKeys(configPath string) (map[string]bool, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool)
+	for key := range values {
+		keys[key] = true
+	}
+
+	return keys, nil
+}
+
+// GetConfigValues lists every setting with its effective value and the file
+// it came from. Later layers win: defaults, the user config and finally the
+// repository's .igitt.yaml, whose commands and shortcuts only count once the
+// user trusts it.
+func GetConfigValues() ([]ConfigValue, error) {
+	config := loadConfigOrWarn()
+
+	layers := []string{GetConfigPath(false)}
+	repoConfigPath := GetRepoConfigPath()
+	if repoConfigPath != "" {
+		layers = append(layers, repoConfigPath)
+	}
+
+	origins := make(map[string]string)
+	for _, layer := range layers {
+		keys, err := readConfigKeys(layer)
+		if err != nil {
+			// loadConfigOrWarn has reported the file already
+			continue
+		}
+		ignoreTrustedKeys := layer == repoConfigPath && !isRepoConfigTrusted(repoConfigPath)
+		for key := range keys {
+			if ignoreTrustedKeys && slices.Contains(trustedKeys, key) {
+				continue
+			}
+			origins[key] = layer
+		}
+	}
+
+	var values []ConfigValue
+
+	for _, setting := range GetSettings() {
+		origin, found := origins[setting.Key]
+		if !found {
+			origin = defaultOrigin
+		}
+
+		values = append(values, ConfigValue{
+			Key:    setting.Key,
+			Value:  setting.GetValue(config),
+			Origin: origin,
+		})
+	}
+
+	return values, nil
+}
+
+func PrintConfigOrigins() error {
+	values, err := GetConfigValues()
+	if err != nil {
+		return err
+	}
+
+	maxOriginWidth := 0
+	for _, value := range values {
+		maxOriginWidth = max(maxOriginWidth, len(value.Origin))
+	}
+
+	for _, value := range values {
+		origin := fmt.Sprintf("%-*s", maxOriginWidth, value.Origin)
+		if value.Origin == defaultOrigin {
+			origin = color.HiBlackString(origin)
+		} else {
+			origin = color.BlueString(origin)
+		}
+
+		fmt.Printf("%s  %s=%s\n", origin, value.Key, color.HiGreenString(value.Value))
+	}
+
+	return nil
+}
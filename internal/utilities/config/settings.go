@@ -0,0 +1,580 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"gopkg.in/yaml.v3"
+)
+
+// Setting describes one key of IgittConfig, taken from the struct tags so
+// new fields are picked up without touching this file.
+type Setting struct {
+	Key      string
+	Kind     reflect.Kind
+	Choices  []string
+	Default  string
+	Comment  string
+	Internal bool
+	field    int
+}
+
+type ValidationProblem struct {
+	Line    int
+	Message string
+}
+
+func (p ValidationProblem) String() string {
+	if p.Line > 0 {
+		return locale.Get("config.problemLine", p.Line, p.Message)
+	}
+	return p.Message
+}
+
+// settingChoices are the choices that depend on what igitt was built with,
+// instead of being listed in the choices tag.
+var settingChoices = map[string]func() []string{
+	"language": func() []string {
+		return append([]string{locale.Auto}, locale.Supported()...)
+	},
+}
+
+// getAllSettings includes the settings igitt manages itself, like the
+// schema version, which users should not edit.
+func getAllSettings() []Setting {
+	var settings []Setting
+
+	configType := reflect.TypeOf(IgittConfig{})
+
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+
+		setting := Setting{
+			Key:      key,
+			Kind:     field.Type.Kind(),
+			Default:  field.Tag.Get("default"),
+			Comment:  field.Tag.Get("comment"),
+			Internal: field.Tag.Get("internal") == "true",
+			field:    i,
+		}
+		if choices := field.Tag.Get("choices"); choices != "" {
+			setting.Choices = strings.Split(choices, ",")
+		}
+		if getChoices, found := settingChoices[key]; found {
+			setting.Choices = getChoices()
+		}
+
+		settings = append(settings, setting)
+	}
+
+	return settings
+}
+
+// GetSettings returns the settings that take a single value. Lists and maps
+// like the custom commands are only edited in the file.
+func GetSettings() []Setting {
+	var settings []Setting
+
+	for _, setting := range getAllSettings() {
+		if !setting.Internal && !setting.hasEntries() {
+			settings = append(settings, setting)
+		}
+	}
+
+	return settings
+}
+
+func getAnySetting(key string) (Setting, bool) {
+	for _, setting := range getAllSettings() {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+
+	return Setting{}, false
+}
+
+func GetSetting(key string) (Setting, error) {
+	for _, setting := range GetSettings() {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+
+	if setting, found := getAnySetting(key); found && setting.hasEntries() {
+		return Setting{}, errors.New(locale.Get("config.hasEntries", key))
+	}
+
+	return Setting{}, errors.New(locale.Get("config.unknownKey", key, strings.Join(getSettingKeys(), ", ")))
+}
+
+// hasEntries tells whether the setting is a list or a map rather than a
+// single value.
+func (s Setting) hasEntries() bool {
+	return s.Kind == reflect.Slice || s.Kind == reflect.Map
+}
+
+func getSettingKeys() []string {
+	var keys []string
+	for _, setting := range GetSettings() {
+		keys = append(keys, setting.Key)
+	}
+	return keys
+}
+
+func (s Setting) GetValue(config IgittConfig) string {
+	return fmt.Sprint(reflect.ValueOf(config).Field(s.field).Interface())
+}
+
+func (s Setting) TypeName() string {
+	if len(s.Choices) > 0 {
+		return locale.Get("config.type.choices", strings.Join(s.Choices, ", "))
+	}
+	if s.Kind == reflect.Bool {
+		return locale.Get("config.type.bool")
+	}
+	return locale.Get("config.type." + s.Kind.String())
+}
+
+// Normalize checks a value against the setting's type and choices and
+// returns it in the form it is written to the file.
+func (s Setting) Normalize(value string) (string, error) {
+	invalid := errors.New(locale.Get("config.invalidValue", value, s.Key, s.TypeName()))
+
+	switch s.Kind {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", invalid
+		}
+		return strconv.FormatBool(parsed), nil
+
+	case reflect.Int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return "", invalid
+		}
+		return strconv.Itoa(parsed), nil
+	}
+
+	if len(s.Choices) == 0 {
+		return value, nil
+	}
+
+	index := slices.IndexFunc(s.Choices, func(choice string) bool {
+		return strings.EqualFold(choice, value)
+	})
+	if index == -1 {
+		return "", invalid
+	}
+
+	return s.Choices[index], nil
+}
+
+func (s Setting) formatDefault() string {
+	if s.Kind == reflect.String {
+		return strconv.Quote(s.Default)
+	}
+	return s.Default
+}
+
+func (s Setting) yamlTag() string {
+	switch s.Kind {
+	case reflect.Bool:
+		return "!!bool"
+	case reflect.Int:
+		return "!!int"
+	}
+	return "!!str"
+}
+
+func GetValue(key string) (string, error) {
+	setting, err := GetSetting(key)
+	if err != nil {
+		return "", err
+	}
+
+	return setting.GetValue(loadConfigOrWarn()), nil
+}
+
+func readConfigDocument(content []byte) (*yaml.Node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	if document.Kind == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	if document.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New(locale.Get("config.notMapping"))
+	}
+
+	return &document, nil
+}
+
+func encodeNode(node *yaml.Node) (string, error) {
+	var content strings.Builder
+
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(node); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	return content.String(), nil
+}
+
+// replaceScalar swaps the value on its line in the original text. Encoding
+// the whole tree would keep comments too, but loses the blank lines between
+// the sections of the file.
+func replaceScalar(content []byte, valueNode *yaml.Node) ([]byte, bool) {
+	if valueNode.Kind != yaml.ScalarNode || valueNode.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return nil, false
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if valueNode.Line < 1 || valueNode.Line > len(lines) {
+		return nil, false
+	}
+
+	line := strings.TrimSuffix(lines[valueNode.Line-1], "\r")
+	if valueNode.Column < 1 || valueNode.Column > len(line)+1 {
+		return nil, false
+	}
+
+	encoded, err := encodeNode(valueNode)
+	if err != nil {
+		return nil, false
+	}
+
+	// the encoded scalar carries its line comment along
+	lines[valueNode.Line-1] = line[:valueNode.Column-1] + strings.TrimSuffix(encoded, "\n")
+	return []byte(strings.Join(lines, "\n")), true
+}
+
+// SetValue writes a single key into the config file at configPath, leaving
+// the rest of the file including the user's comments untouched.
+func SetValue(configPath string, key string, value string) (string, error) {
+	values, err := SetValues(configPath, []ConfigValue{{Key: key, Value: value}})
+	if err != nil {
+		return "", err
+	}
+
+	return values[0].Value, nil
+}
+
+// SetValues validates all values first and then writes them in one go, so
+// either every change is saved or none. It returns the normalized values.
+func SetValues(configPath string, values []ConfigValue) ([]ConfigValue, error) {
+	var normalized []ConfigValue
+
+	for _, value := range values {
+		setting, err := GetSetting(value.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		normalizedValue, err := setting.Normalize(value.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		normalized = append(normalized, ConfigValue{Key: value.Key, Value: normalizedValue, Origin: configPath})
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for _, value := range normalized {
+		setting, _ := GetSetting(value.Key)
+
+		content, err = applyValue(content, setting, value.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return normalized, writeConfigFile(configPath, content)
+}
+
+func applyValue(content []byte, setting Setting, value string) ([]byte, error) {
+	document, err := readConfigDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := document.Content[0]
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != setting.Key {
+			continue
+		}
+
+		valueNode := mapping.Content[i+1]
+		valueNode.Kind = yaml.ScalarNode
+		valueNode.Tag = setting.yamlTag()
+		valueNode.Value = value
+
+		if replaced, ok := replaceScalar(content, valueNode); ok {
+			return replaced, nil
+		}
+
+		encoded, err := encodeNode(document)
+		return []byte(encoded), err
+	}
+
+	valueNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: setting.yamlTag(), Value: value}
+	if setting.Kind == reflect.String {
+		valueNode.Style = yaml.DoubleQuotedStyle
+	}
+
+	encoded, err := encodeNode(&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: setting.Key},
+		valueNode,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+
+	return append(content, encoded...), nil
+}
+
+// checkWritable refuses files the user made read-only. Replacing them
+// through a temporary file would succeed even so.
+func checkWritable(configPath string) error {
+	info, err := os.Stat(configPath)
+	if err != nil || info.Mode().Perm()&0200 != 0 {
+		return nil
+	}
+
+	return &os.PathError{Op: "write", Path: configPath, Err: os.ErrPermission}
+}
+
+// writeConfigFile replaces the file in one step, so an interrupted write
+// never leaves a half written configuration behind.
+func writeConfigFile(configPath string, content []byte) error {
+	if err := checkWritable(configPath); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(configPath), filepath.Base(configPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(configPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(file.Name(), mode); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), configPath)
+}
+
+// ValidateConfigFile reports syntax errors, unknown keys and values of the
+// wrong type, each with the line it was found on.
+func ValidateConfigFile(configPath string) ([]ValidationProblem, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return []ValidationProblem{{Message: err.Error()}}, nil
+	}
+
+	if document.Kind == 0 {
+		return nil, nil
+	}
+
+	mapping := document.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return []ValidationProblem{{Line: mapping.Line, Message: locale.Get("config.notMapping")}}, nil
+	}
+
+	var problems []ValidationProblem
+	seen := make(map[string]int)
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keyNode := mapping.Content[i]
+		valueNode := mapping.Content[i+1]
+
+		if line, duplicate := seen[keyNode.Value]; duplicate {
+			problems = append(problems, ValidationProblem{keyNode.Line, locale.Get("config.duplicateKey", keyNode.Value, line)})
+			continue
+		}
+		seen[keyNode.Value] = keyNode.Line
+
+		setting, found := getAnySetting(keyNode.Value)
+		if !found {
+			_, err := GetSetting(keyNode.Value)
+			problems = append(problems, ValidationProblem{keyNode.Line, err.Error()})
+			continue
+		}
+
+		if setting.Kind == reflect.Slice {
+			problems = append(problems, validateCustomCommands(valueNode)...)
+			continue
+		}
+
+		if setting.Kind == reflect.Map {
+			problems = append(problems, validateShortcuts(valueNode)...)
+			continue
+		}
+
+		if valueNode.Kind != yaml.ScalarNode {
+			problems = append(problems, ValidationProblem{valueNode.Line, locale.Get("config.wrongType", setting.Key, setting.TypeName())})
+			continue
+		}
+
+		if setting.Kind != reflect.String && valueNode.Tag != setting.yamlTag() {
+			problems = append(problems, ValidationProblem{valueNode.Line, locale.Get("config.invalidValue", valueNode.Value, setting.Key, setting.TypeName())})
+			continue
+		}
+
+		if _, err := setting.Normalize(valueNode.Value); err != nil {
+			problems = append(problems, ValidationProblem{valueNode.Line, err.Error()})
+		}
+	}
+
+	return problems, nil
+}
+
+func PrintConfigValues() error {
+	values, err := GetConfigValues()
+	if err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		fmt.Printf("%s=%s\n", value.Key, color.HiGreenString(value.Value))
+	}
+
+	return nil
+}
+
+// ValidateConfig checks the user configuration and the repository's
+// .igitt.yaml and prints every problem found.
+func ValidateConfig() error {
+	configPaths := []string{GetConfigPath(false)}
+	if repoConfigPath := GetRepoConfigPath(); repoConfigPath != "" {
+		configPaths = append(configPaths, repoConfigPath)
+	}
+
+	problemCount := 0
+
+	for _, configPath := range configPaths {
+		problems, err := ValidateConfigFile(configPath)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			fmt.Printf("%s %s\n", color.HiGreenString(plain.Label("✓", locale.Get("plain.ok"))), configPath)
+			continue
+		}
+
+		fmt.Printf("%s %s\n", color.HiRedString(plain.Label("✗", locale.Get("plain.failed"))), configPath)
+		for _, problem := range problems {
+			fmt.Printf("    %s\n", problem)
+		}
+		problemCount += len(problems)
+	}
+
+	if untrustedPath := GetUntrustedRepoConfig(); untrustedPath != "" {
+		fmt.Println(color.HiYellowString("\n" + locale.Get("trust.untrusted", untrustedPath)))
+	}
+
+	if problemCount > 0 {
+		return errors.New(locale.Get("config.problems", problemCount))
+	}
+
+	return nil
+}
+
+// DisableUnknownKeys comments out the top-level keys igitt does not know,
+// including their nested values, instead of deleting them. It returns the
+// keys it disabled.
+func DisableUnknownKeys(configPath string) ([]string, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := readConfigDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	mapping := document.Content[0]
+
+	var disabled []string
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keyNode := mapping.Content[i]
+		if _, found := getAnySetting(keyNode.Value); found {
+			continue
+		}
+
+		// the value ends before the next key, minus the comments and blank
+		// lines that belong to that key
+		last := len(lines)
+		if i+2 < len(mapping.Content) {
+			last = mapping.Content[i+2].Line - 1
+		}
+		for last > keyNode.Line && (strings.TrimSpace(lines[last-1]) == "" || strings.HasPrefix(lines[last-1], "#")) {
+			last--
+		}
+
+		for line := keyNode.Line; line <= last; line++ {
+			lines[line-1] = "# " + lines[line-1]
+		}
+
+		disabled = append(disabled, keyNode.Value)
+	}
+
+	if len(disabled) == 0 {
+		return nil, nil
+	}
+
+	return disabled, writeConfigFile(configPath, []byte(strings.Join(lines, "\n")))
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"gopkg.in/yaml.v3"
+)
+
+const shortcutsExample = `The entries are op-status, op-commit, op-add, op-pull, op-push, op-branches,
+op-worktrees, op-undo, op-clone, op-init, igitt-config and exit, your own
+commands are custom-<id> and plugins plugin-<name>. For example:
+shortcuts:
+  op-commit: "c"
+  op-branches: "b, br"
+  op-undo: "none"
+  custom-update-from-main: "um"`
+
+func validateShortcuts(node *yaml.Node) []ValidationProblem {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return []ValidationProblem{{node.Line, locale.Get("shortcuts.notMapping")}}
+	}
+
+	var problems []ValidationProblem
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		valueNode := node.Content[i+1]
+
+		if valueNode.Kind != yaml.ScalarNode || valueNode.Tag != "!!str" {
+			problems = append(problems, ValidationProblem{valueNode.Line, locale.Get("shortcuts.notString", keyNode.Value)})
+		}
+	}
+
+	return problems
+}
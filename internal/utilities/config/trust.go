@@ -0,0 +1,159 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"gopkg.in/yaml.v3"
+)
+
+const trustFileName = "trusted.yaml"
+
+const trustHeader = "# The repositories whose .igitt.yaml may add commands and shortcuts, change them with igitt config trust.\n"
+
+// trustedKeys are the settings of a repository's .igitt.yaml that run
+// commands, they are only taken from repositories the user trusts.
+var trustedKeys = []string{"commands", "shortcuts"}
+
+// TrustedRepository is a repository whose .igitt.yaml the user trusts. The
+// checksum is the one of the file when it was trusted, a changed file has to
+// be trusted again.
+type TrustedRepository struct {
+	Path     string `yaml:"path"`
+	Checksum string `yaml:"checksum"`
+}
+
+type trustList struct {
+	Repositories []TrustedRepository `yaml:"repositories"`
+}
+
+// getTrustPath returns the trust list in the igitt directory of
+// $XDG_CONFIG_HOME. It is never read from a repository.
+func getTrustPath() string {
+	return filepath.Join(filepath.Dir(getXdgConfigPath()), trustFileName)
+}
+
+func readTrustList() (trustList, error) {
+	var trusted trustList
+
+	content, err := os.ReadFile(getTrustPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return trusted, nil
+	}
+	if err != nil {
+		return trusted, err
+	}
+
+	err = yaml.Unmarshal(content, &trusted)
+	return trusted, err
+}
+
+func saveTrustList(trusted trustList) error {
+	content, err := yaml.Marshal(trusted)
+	if err != nil {
+		return err
+	}
+
+	return writeConfigFile(getTrustPath(), append([]byte(trustHeader), content...))
+}
+
+func getChecksum(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isRepoConfigTrusted tells whether the repository's .igitt.yaml was trusted
+// as it is now.
+func isRepoConfigTrusted(repoConfigPath string) bool {
+	trusted, err := readTrustList()
+	if err != nil {
+		logger.ErrorLogger.Println("Failed to read the trusted repositories:", err)
+		return false
+	}
+
+	checksum, err := getChecksum(repoConfigPath)
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(trusted.Repositories, TrustedRepository{Path: filepath.Dir(repoConfigPath), Checksum: checksum})
+}
+
+// setsTrustedKeys tells whether a configuration file sets commands or
+// shortcuts.
+func setsTrustedKeys(configPath string) bool {
+	keys, err := readConfigKeys(configPath)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(trustedKeys, func(key string) bool {
+		return keys[key]
+	})
+}
+
+// GetUntrustedRepoConfig returns the path of the current repository's
+// .igitt.yaml if it sets commands or shortcuts that are ignored because the
+// user has not trusted it, and an empty string otherwise.
+func GetUntrustedRepoConfig() string {
+	repoConfigPath := GetRepoConfigPath()
+	if repoConfigPath == "" || !setsTrustedKeys(repoConfigPath) || isRepoConfigTrusted(repoConfigPath) {
+		return ""
+	}
+
+	return repoConfigPath
+}
+
+// TrustRepoConfig lets the repository's .igitt.yaml add commands and
+// shortcuts, as long as it does not change.
+func TrustRepoConfig(repoConfigPath string) error {
+	checksum, err := getChecksum(repoConfigPath)
+	if err != nil {
+		return err
+	}
+
+	trusted, err := readTrustList()
+	if err != nil {
+		return err
+	}
+
+	repoRoot := filepath.Dir(repoConfigPath)
+	trusted.Repositories = slices.DeleteFunc(trusted.Repositories, func(repository TrustedRepository) bool {
+		return repository.Path == repoRoot
+	})
+	trusted.Repositories = append(trusted.Repositories, TrustedRepository{Path: repoRoot, Checksum: checksum})
+
+	logger.InfoLogger.Println("Trusted the repository configuration:", repoConfigPath)
+	return saveTrustList(trusted)
+}
+
+// UntrustRepoConfig removes the repository from the trust list. It returns
+// false if it was not trusted.
+func UntrustRepoConfig(repoConfigPath string) (bool, error) {
+	trusted, err := readTrustList()
+	if err != nil {
+		return false, err
+	}
+
+	repoRoot := filepath.Dir(repoConfigPath)
+	count := len(trusted.Repositories)
+	trusted.Repositories = slices.DeleteFunc(trusted.Repositories, func(repository TrustedRepository) bool {
+		return repository.Path == repoRoot
+	})
+	if len(trusted.Repositories) == count {
+		return false, nil
+	}
+
+	logger.InfoLogger.Println("Removed the trust of the repository configuration:", repoConfigPath)
+	return true, saveTrustList(trusted)
+}
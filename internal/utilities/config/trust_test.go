@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTrustRepoConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	repoConfigPath := writeFileForTest(t, "shortcuts:\n  commit: \"c\"\n")
+
+	if isRepoConfigTrusted(repoConfigPath) {
+		t.Fatal("a new repository is trusted")
+	}
+
+	if err := TrustRepoConfig(repoConfigPath); err != nil {
+		t.Fatal(err)
+	}
+	if !isRepoConfigTrusted(repoConfigPath) {
+		t.Fatal("the repository is not trusted after trusting it")
+	}
+
+	// trusting again replaces the entry instead of adding a second one
+	if err := TrustRepoConfig(repoConfigPath); err != nil {
+		t.Fatal(err)
+	}
+	trusted, err := readTrustList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trusted.Repositories) != 1 {
+		t.Errorf("trust list holds %d entries, want 1", len(trusted.Repositories))
+	}
+
+	if err := os.WriteFile(repoConfigPath, []byte("shortcuts:\n  commit: \"cmt\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if isRepoConfigTrusted(repoConfigPath) {
+		t.Error("a changed file is still trusted")
+	}
+
+	removed, err := UntrustRepoConfig(repoConfigPath)
+	if err != nil || !removed {
+		t.Fatalf("UntrustRepoConfig() = %v, %v, want true, nil", removed, err)
+	}
+	removed, err = UntrustRepoConfig(repoConfigPath)
+	if err != nil || removed {
+		t.Errorf("UntrustRepoConfig() of an untrusted repository = %v, %v, want false, nil", removed, err)
+	}
+}
+
+func TestSetsTrustedKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"commands", "commands:\n  - id: \"x\"\n", true},
+		{"shortcuts", "shortcuts:\n  commit: \"c\"\n", true},
+		{"other settings", "showAllCommands: true\n", false},
+		{"empty", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := setsTrustedKeys(writeFileForTest(t, test.content)); got != test.want {
+				t.Errorf("setsTrustedKeys() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
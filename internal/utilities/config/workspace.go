@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+const workspaceFileName = "workspace.yaml"
+
+const workspaceHeader = "# The repositories of igitt workspace, change them with igitt workspace add and remove.\n"
+
+// Workspace is the saved list of repositories igitt workspace runs in when
+// it is not given a directory to search.
+type Workspace struct {
+	Repositories []string `yaml:"repositories"`
+}
+
+// GetWorkspacePath returns the workspace file in the igitt directory of
+// $XDG_CONFIG_HOME, also when the configuration is read from $IGITT_CONFIG
+// or next to the executable.
+func GetWorkspacePath() string {
+	return filepath.Join(filepath.Dir(getXdgConfigPath()), workspaceFileName)
+}
+
+// ReadWorkspace reads the saved repositories, a missing file is an empty
+// workspace.
+func ReadWorkspace() (Workspace, error) {
+	var workspace Workspace
+
+	content, err := os.ReadFile(GetWorkspacePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return workspace, nil
+	}
+	if err != nil {
+		return workspace, err
+	}
+
+	err = yaml.Unmarshal(content, &workspace)
+	return workspace, err
+}
+
+// SaveWorkspace writes the repositories sorted and without duplicates.
+func SaveWorkspace(workspace Workspace) error {
+	slices.Sort(workspace.Repositories)
+	workspace.Repositories = slices.Compact(workspace.Repositories)
+
+	content, err := yaml.Marshal(workspace)
+	if err != nil {
+		return err
+	}
+
+	return writeConfigFile(GetWorkspacePath(), append([]byte(workspaceHeader), content...))
+}
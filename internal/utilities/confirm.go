@@ -0,0 +1,46 @@
+package utilities
+
+import (
+	"errors"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/mattn/go-isatty"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
+)
+
+// AssumeYes answers every confirmation with yes, so scripts and CI jobs can
+// run the operations that ask first.
+var AssumeYes bool
+
+// ErrNoTerminal is returned by Confirm when there is nobody to ask.
+var ErrNoTerminal = errors.New("cannot ask for confirmation without a terminal, pass --yes to confirm")
+
+// IsTerminalInput tells whether igitt can ask the user, it cannot when the
+// input comes from a pipe or a CI job.
+func IsTerminalInput() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+}
+
+// Confirm asks a yes or no question before a destructive operation.
+func Confirm(title string, description string) (bool, error) {
+	if AssumeYes {
+		return true, nil
+	}
+
+	if !IsTerminalInput() {
+		return false, ErrNoTerminal
+	}
+
+	var confirmed bool
+
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(plain.Title(title, description)).
+				Description(description).
+				Value(&confirmed))).WithTheme(theme.GetHuhTheme()).WithAccessible(plain.Enabled).Run()
+
+	return confirmed, err
+}
@@ -0,0 +1,259 @@
+package giterrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
+
+	_ "embed"
+)
+
+//go:embed errors.json
+var errorsJSON []byte
+
+type Suggestion struct {
+	Title   string   `json:"title"`
+	Command []string `json:"command"`
+	Action  string   `json:"action"`
+}
+
+type KnownError struct {
+	Id          string       `json:"id"`
+	Title       string       `json:"title"`
+	Patterns    []string     `json:"patterns"`
+	Explanation string       `json:"explanation"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+var knownErrors []KnownError
+
+// RunCommand runs the git command of a suggested fix. The git operations set
+// it, so a fix is explained, dry-run and journaled like any other operation.
+var RunCommand func(arguments []string) error
+
+// Command returns a git command that prints its messages in English, the
+// patterns of the catalog only match those. The explanation shown instead
+// is translated.
+func Command(arguments ...string) *exec.Cmd {
+	command := exec.Command("git", arguments...)
+	command.Env = append(os.Environ(), "LC_ALL=C", "LANGUAGE=C")
+	return command
+}
+
+func getKnownErrors() []KnownError {
+	if knownErrors == nil {
+		err := json.Unmarshal(errorsJSON, &knownErrors)
+		if err != nil {
+			logger.ErrorLogger.Println("Failed to read error catalog:", err)
+			knownErrors = []KnownError{}
+		}
+	}
+
+	return knownErrors
+}
+
+// Match finds the catalog entry for git's output and fills in the
+// placeholders ({branch}, {remote}) of its texts and commands.
+func Match(output string) (KnownError, bool) {
+	for _, knownError := range getKnownErrors() {
+		for _, pattern := range knownError.Patterns {
+			matched, err := regexp.MatchString(pattern, output)
+			if err != nil {
+				logger.ErrorLogger.Println("Invalid pattern in error catalog:", knownError.Id, err)
+				continue
+			}
+
+			if matched {
+				logger.InfoLogger.Println("Recognized git error:", knownError.Id)
+				return fillPlaceholders(translate(knownError)), true
+			}
+		}
+	}
+
+	return KnownError{}, false
+}
+
+// translate replaces the English texts of errors.json with the ones of the
+// current language, the commands stay the same.
+func translate(knownError KnownError) KnownError {
+	prefix := "gitError." + knownError.Id + "."
+
+	translated := knownError
+	if title, found := locale.Lookup(prefix + "title"); found {
+		translated.Title = title
+	}
+	if explanation, found := locale.Lookup(prefix + "explanation"); found {
+		translated.Explanation = explanation
+	}
+
+	translated.Suggestions = slices.Clone(knownError.Suggestions)
+	for i := range translated.Suggestions {
+		if title, found := locale.Lookup(fmt.Sprintf("%ssuggestion.%d", prefix, i+1)); found {
+			translated.Suggestions[i].Title = title
+		}
+	}
+
+	return translated
+}
+
+func readGit(arguments ...string) string {
+	byteOut, _ := exec.Command("git", arguments...).Output()
+	return strings.TrimSpace(string(byteOut))
+}
+
+func fillPlaceholders(knownError KnownError) KnownError {
+	branch := readGit("symbolic-ref", "--quiet", "--short", "HEAD")
+	if branch == "" {
+		branch = "<branch>"
+	}
+
+	remote, _, _ := strings.Cut(readGit("remote"), "\n")
+	if remote == "" {
+		remote = "origin"
+	}
+
+	replacer := strings.NewReplacer("{branch}", branch, "{remote}", remote)
+
+	filled := knownError
+	filled.Title = replacer.Replace(knownError.Title)
+	filled.Explanation = replacer.Replace(knownError.Explanation)
+	filled.Suggestions = make([]Suggestion, len(knownError.Suggestions))
+
+	for i, suggestion := range knownError.Suggestions {
+		filled.Suggestions[i] = Suggestion{
+			Title:  replacer.Replace(suggestion.Title),
+			Action: suggestion.Action,
+		}
+		for _, argument := range suggestion.Command {
+			filled.Suggestions[i].Command = append(filled.Suggestions[i].Command, replacer.Replace(argument))
+		}
+	}
+
+	return filled
+}
+
+func (s Suggestion) IsRunnable() bool {
+	return len(s.Command) > 0 || s.Action != ""
+}
+
+func (s Suggestion) CommandLine() string {
+	if len(s.Command) > 0 {
+		return "git " + strings.Join(s.Command, " ")
+	}
+	return ""
+}
+
+func Print(knownError KnownError, spacing string) {
+	fmt.Printf("\n%s", theme.ErrorBorder(spacing))
+	fmt.Printf("%s  %s\n\n", theme.ErrorTitle(plain.Label("⚠", locale.Get("plain.error"))), theme.ErrorTitle(knownError.Title))
+	fmt.Printf("%s\n", knownError.Explanation)
+
+	if len(knownError.Suggestions) > 0 {
+		fmt.Printf("\n%s\n\n", color.New(color.Bold).Sprint(locale.Get("gitError.whatYouCanDo")))
+
+		for i, suggestion := range knownError.Suggestions {
+			fmt.Printf("  %d. %s\n", i+1, suggestion.Title)
+			if suggestion.CommandLine() != "" {
+				fmt.Printf("     %s\n", color.CyanString(suggestion.CommandLine()))
+			}
+		}
+	}
+}
+
+func PrintRawOutput(output string) {
+	fmt.Printf("\n%s\n\n%s\n", color.HiBlackString(locale.Get("gitError.originalMessage")), theme.ErrorMessage(strings.TrimSpace(output)))
+}
+
+const (
+	choiceShowRaw = -1
+	choiceDismiss = -2
+)
+
+// OfferSuggestions lets the user pick one of the runnable suggestions, or
+// reveal the raw git output, until they dismiss the prompt.
+func OfferSuggestions(knownError KnownError, output string, rawShown bool) {
+	for {
+		var options []huh.Option[int]
+
+		for i, suggestion := range knownError.Suggestions {
+			if suggestion.IsRunnable() {
+				options = append(options, huh.NewOption(suggestion.Title, i))
+			}
+		}
+
+		if !rawShown {
+			options = append(options, huh.NewOption(locale.Get("gitError.showOriginal"), choiceShowRaw))
+		}
+		options = append(options, huh.NewOption(locale.Get("gitError.doNothing"), choiceDismiss))
+
+		choice := choiceDismiss
+
+		err := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title(locale.Get("gitError.whatToDo")).
+					Options(options...).
+					Value(&choice))).WithTheme(theme.GetHuhTheme()).WithAccessible(plain.Enabled).Run()
+
+		if err != nil || choice == choiceDismiss {
+			return
+		}
+
+		if choice == choiceShowRaw {
+			PrintRawOutput(output)
+			rawShown = true
+			continue
+		}
+
+		runSuggestion(knownError.Suggestions[choice])
+		return
+	}
+}
+
+func runSuggestion(suggestion Suggestion) {
+	logger.InfoLogger.Println("Running suggested fix:", suggestion.Title)
+
+	if suggestion.Action == "remove-index-lock" {
+		removeIndexLock()
+		return
+	}
+
+	if err := RunCommand(suggestion.Command); err != nil {
+		logger.ErrorLogger.Println("Suggested fix failed:", err)
+		fmt.Println(color.HiRedString(locale.Get("gitError.fixFailed")))
+		return
+	}
+
+	logger.InfoLogger.Println("Suggested fix succeeded:", suggestion.CommandLine())
+}
+
+func removeIndexLock() {
+	gitDir := readGit("rev-parse", "--absolute-git-dir")
+	if gitDir == "" {
+		fmt.Println(color.HiRedString(locale.Get("gitError.noGitDir")))
+		return
+	}
+
+	lockFile := filepath.Join(gitDir, "index.lock")
+
+	if err := os.Remove(lockFile); err != nil {
+		logger.ErrorLogger.Println("Failed to remove index.lock:", err)
+		fmt.Println(color.HiRedString(locale.Get("gitError.lockNotRemoved", lockFile, err)))
+		return
+	}
+
+	logger.InfoLogger.Println("Removed stale lock file:", lockFile)
+	fmt.Println(locale.Get("gitError.lockRemoved", color.HiGreenString(lockFile)))
+}
@@ -75,3 +75,29 @@ func GetCommitIcon(variant IconType) string {
 	}
 	return "✎  "
 }
+
+func GetSuccessIcon(variant IconType) string {
+	if variant == Emoji {
+		return "✅"
+	}
+	if variant == NerdFont {
+		return ""
+	}
+	if variant == Ascii {
+		return "[ok]"
+	}
+	return "✓"
+}
+
+func GetFailureIcon(variant IconType) string {
+	if variant == Emoji {
+		return "❌"
+	}
+	if variant == NerdFont {
+		return ""
+	}
+	if variant == Ascii {
+		return "[!!]"
+	}
+	return "✗"
+}
@@ -0,0 +1,86 @@
+package initialize
+
+import (
+	"github.com/nstr-dev/igitt/internal/operations"
+	"github.com/spf13/cobra"
+)
+
+const aliasInstallLong = `Create igt as a short name for igitt.
+
+By default a small sh script (igt.cmd on Windows) is put next to the igitt
+executable. Pass --dir to put it into another directory on your PATH, e.g.
+~/.local/bin, and --symlink to link to the executable instead of a script.
+Aliases found elsewhere that start an igitt which does not exist anymore are
+updated as well.
+
+If no directory on your PATH is writable, print a shell alias instead and add
+it to your shell's rc file:
+  igitt alias install --shell bash >> ~/.bashrc
+  igitt alias install --shell zsh >> ~/.zshrc
+  igitt alias install --shell fish >> ~/.config/fish/config.fish`
+
+func newAliasCommand() *cobra.Command {
+	var installDir, installShell string
+	var installSymlink bool
+
+	var aliasCmd = &cobra.Command{
+		Use:   "alias",
+		Short: "Manage igt, the short name for igitt",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operations.PrintAliasStatus()
+		},
+	}
+
+	var installCmd = &cobra.Command{
+		Use:   "install",
+		Short: "Create the igt alias or update outdated ones",
+		Long:  aliasInstallLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if installShell != "" {
+				return operations.PrintShellAlias(installShell)
+			}
+
+			kind := operations.AliasScript
+			if installSymlink {
+				kind = operations.AliasSymlink
+			}
+
+			return operations.InstallAlias(installDir, kind)
+		},
+	}
+	installCmd.Flags().StringVar(&installDir, "dir", "", "Directory to create the alias in (default: next to the igitt executable)")
+	installCmd.Flags().BoolVar(&installSymlink, "symlink", false, "Create a symlink to the executable instead of a script")
+	installCmd.Flags().StringVar(&installShell, "shell", "", "Print a shell alias for bash, zsh or fish instead of creating a file")
+	installCmd.MarkFlagsMutuallyExclusive("shell", "dir")
+	installCmd.MarkFlagsMutuallyExclusive("shell", "symlink")
+	installCmd.RegisterFlagCompletionFunc("shell", cobra.FixedCompletions([]string{"bash", "zsh", "fish"}, cobra.ShellCompDirectiveNoFileComp))
+	installCmd.MarkFlagDirname("dir")
+
+	var uninstallDir string
+
+	var uninstallCmd = &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the igt aliases created by igitt",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operations.UninstallAlias(uninstallDir)
+		},
+	}
+	uninstallCmd.Flags().StringVar(&uninstallDir, "dir", "", "Also look for the alias in this directory")
+	uninstallCmd.MarkFlagDirname("dir")
+
+	var statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show where igt aliases are and whether they are up to date",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operations.PrintAliasStatus()
+		},
+	}
+
+	aliasCmd.AddCommand(installCmd, uninstallCmd, statusCmd)
+
+	return aliasCmd
+}
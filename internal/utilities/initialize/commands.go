@@ -1,6 +1,8 @@
 package initialize
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strings"
 
@@ -8,22 +10,52 @@ import (
 	"github.com/nstr-dev/igitt/internal/operations"
 	"github.com/nstr-dev/igitt/internal/operations/git"
 	"github.com/nstr-dev/igitt/internal/operations/interactive"
+	"github.com/nstr-dev/igitt/internal/utilities"
 	"github.com/nstr-dev/igitt/internal/utilities/config"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/plugins"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
 	"github.com/nstr-dev/igitt/internal/utilities/welcome"
 	"github.com/spf13/cobra"
 )
 
+// isRepairCommand tells whether the config or doctor command runs, which
+// must work even if the configuration cannot be read.
+func isRepairCommand(rootCmd *cobra.Command) bool {
+	command, _, err := rootCmd.Find(os.Args[1:])
+	if err != nil {
+		return false
+	}
+
+	for ; command != nil && command != rootCmd; command = command.Parent() {
+		if command.Name() == "config" || command.Name() == "doctor" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func InitializeIgitt(version string, commit string, buildDate string) {
 	cyan := color.New(color.FgCyan).SprintfFunc()
 	heading := color.New(color.Bold, color.FgGreen).SprintfFunc()
 
+	var createdNewConfig bool
+
 	var rootCmd = &cobra.Command{
 		Use:   "igitt",
 		Short: "Igitt is an interactive Git client with a CLI.",
 		Long:  `Igitt supercharges your Git experience with an interactive CLI. Designed to enhance learning and streamline workflows, it offers detailed command descriptions and efficient shortcuts for a faster, more intuitive Git journey.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			logger.InfoLogger.Println("igitt was called without arguments")
+
+			if createdNewConfig {
+				welcome.PrintWelcomeMessage()
+				return nil
+			}
+			return interactive.StartInteractive()
 		},
 		Version: ">> Version: " + cyan(version) + "\n>> Commit: " + cyan(commit) + "\n>> Build Date: " + cyan(buildDate),
 	}
@@ -33,76 +65,124 @@ func InitializeIgitt(version string, commit string, buildDate string) {
 			"\n=======================================\n\n{{.Version}}",
 	)
 
+	var cloneRecurseSubmodules bool
+
 	var cloneCmd = &cobra.Command{
 		Use:     "clone [repository]",
 		Short:   "(cln) Clone a repository into a new directory",
 		Aliases: []string{"cln"},
 		Args:    cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			git.CloneRepository(strings.Join(args, " "))
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.CloneRepository(strings.Join(args, " "), cloneRecurseSubmodules)
 		},
 	}
+	cloneCmd.Flags().BoolVarP(&cloneRecurseSubmodules, "recurse-submodules", "r", false, "Also clone and check out all submodules")
 
 	var initCmd = &cobra.Command{
 		Use:   "init",
 		Short: "Create an empty Git repository or reinitialize an existing one",
-		Run: func(cmd *cobra.Command, args []string) {
-			git.InitRepository()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.InitRepository()
 		},
 	}
 
+	var pullRecurseSubmodules bool
+
 	var pullCmd = &cobra.Command{
 		Use:   "pull",
 		Short: "Fetch from and integrate with another repository or a local branch",
-		Run: func(cmd *cobra.Command, args []string) {
-			git.PullRemote()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.PullRemote(pullRecurseSubmodules)
 		},
 	}
+	pullCmd.Flags().BoolVarP(&pullRecurseSubmodules, "recurse-submodules", "r", false, "Also update submodules to the commits recorded after pulling")
 
 	var pushCmd = &cobra.Command{
-		Use:   "push",
-		Short: "Update remote refs along with associated objects",
-		Run: func(cmd *cobra.Command, args []string) {
-			git.PushRemote()
+		Use:               "push [remote]",
+		Short:             "Update remote refs along with associated objects",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeRemotes,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return git.PushToRemote(args[0])
+			}
+			return git.PushRemote()
 		},
 	}
 
+	var commitMessage string
+	var commitOptions git.CommitOptions
+
 	var commitCmd = &cobra.Command{
-		Use:     "commit",
+		Use:     "commit [message]",
 		Short:   "(cmt) Record changes to the repository",
-		Args:    cobra.MinimumNArgs(1),
 		Aliases: []string{"cmt"},
-		Run: func(cmd *cobra.Command, args []string) {
-			git.CommitChanges(strings.Join(args, " "))
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 && commitMessage != "" {
+				return errors.New("pass the commit message either as argument or with --message")
+			}
+			if len(args) == 0 && commitMessage == "" {
+				return errors.New("a commit message is required, pass it as argument or with --message")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			message := commitMessage
+			if len(args) > 0 {
+				message = strings.Join(args, " ")
+			}
+			return git.CommitWithOptions(message, commitOptions)
 		},
 	}
+	commitCmd.Flags().StringVarP(&commitMessage, "message", "m", "", "The commit message")
+	commitCmd.Flags().StringSliceVar(&commitOptions.Paths, "add", nil, "Stage these files before committing, separated by commas or repeated")
+	commitCmd.Flags().BoolVarP(&commitOptions.All, "all", "a", false, "Stage every change before committing")
+	commitCmd.Flags().BoolVar(&commitOptions.Sync, "sync", false, "Pull and push after committing")
+	commitCmd.MarkFlagsMutuallyExclusive("add", "all")
+	commitCmd.RegisterFlagCompletionFunc("add", completeModifiedFiles)
+
+	var addAll bool
 
 	var gitAddCmd = &cobra.Command{
-		Use:     "add",
+		Use:     "add [paths]",
 		Short:   "(a, +) Add file contents to the index",
 		Aliases: []string{"a", "+"},
-		Args:    cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			git.AddChanges(args)
+		Args: func(cmd *cobra.Command, args []string) error {
+			if addAll && len(args) > 0 {
+				return errors.New("pass either paths or --all")
+			}
+			if !addAll && len(args) == 0 {
+				return errors.New("requires at least 1 path, or --all to stage every change")
+			}
+			return nil
+		},
+		ValidArgsFunction: completeModifiedFiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if addAll {
+				return git.AddEverything()
+			}
+			return git.AddChanges(args)
 		},
 	}
+	gitAddCmd.Flags().BoolVarP(&addAll, "all", "A", false, "Stage every change")
 
 	var statusCmd = &cobra.Command{
 		Use:     "status",
 		Short:   "(s) Show changed files",
 		Aliases: []string{"s"},
-		Run: func(cmd *cobra.Command, args []string) {
-			git.Status()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.Status()
 		},
 	}
 
 	var checkoutCmd = &cobra.Command{
-		Use:     "checkout",
-		Short:   "(cout) Change to a different branch",
-		Args:    cobra.MinimumNArgs(1),
-		Aliases: []string{"cout"},
-		Run: func(cmd *cobra.Command, args []string) {
-			git.CheckoutBranch(strings.Join(args, " "))
+		Use:               "checkout",
+		Short:             "(cout) Change to a different branch",
+		Args:              cobra.MinimumNArgs(1),
+		Aliases:           []string{"cout"},
+		ValidArgsFunction: completeBranches,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.CheckoutBranch(strings.Join(args, " "))
 		},
 	}
 
@@ -110,54 +190,492 @@ func InitializeIgitt(version string, commit string, buildDate string) {
 		Use:     "branch",
 		Short:   "(br) Manage branches",
 		Aliases: []string{"br"},
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
-				git.DoCustomBranchAction("")
-				return
+				return git.DoCustomBranchAction("")
+			}
+			return git.DoCustomBranchAction(strings.Join(args, " "))
+		},
+	}
+
+	var branchListCmd = &cobra.Command{
+		Use:     "list",
+		Short:   "(ls) List the local branches",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.DoCustomBranchAction("")
+		},
+	}
+
+	var branchCreateCmd = &cobra.Command{
+		Use:   "create [name]",
+		Short: "Create a branch and check it out",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.CreateBranch(args[0])
+		},
+	}
+
+	var branchCheckoutCmd = &cobra.Command{
+		Use:               "checkout [name]",
+		Short:             "Check out a branch",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeBranches,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.CheckoutBranch(args[0])
+		},
+	}
+
+	var branchRenameCmd = &cobra.Command{
+		Use:               "rename [name] [new name]",
+		Short:             "Rename a branch",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeBranches,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.RenameBranch(args[0], args[1])
+		},
+	}
+
+	var branchDeleteCmd = &cobra.Command{
+		Use:               "delete [name]",
+		Short:             "Delete a branch after asking, --yes skips the question",
+		Aliases:           []string{"rm"},
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeBranches,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.ConfirmDeleteBranch(args[0])
+		},
+	}
+
+	branchCmd.AddCommand(
+		branchListCmd,
+		branchCreateCmd,
+		branchCheckoutCmd,
+		branchRenameCmd,
+		branchDeleteCmd,
+	)
+
+	var worktreeCmd = &cobra.Command{
+		Use:     "worktree",
+		Short:   "(wt) List, create and remove worktrees",
+		Aliases: []string{"wt"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.ListWorktrees()
+		},
+	}
+
+	var worktreeListCmd = &cobra.Command{
+		Use:     "list",
+		Short:   "(ls) List worktrees with their branch and state",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.ListWorktrees()
+		},
+	}
+
+	var worktreeNewBranch bool
+
+	var worktreeAddCmd = &cobra.Command{
+		Use:   "add [path] [branch]",
+		Short: "Create a worktree at the given path for a branch",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.AddWorktree(args[0], args[1], worktreeNewBranch)
+		},
+	}
+	worktreeAddCmd.Flags().BoolVarP(&worktreeNewBranch, "new-branch", "b", false, "Create the branch instead of checking out an existing one")
+
+	var worktreeForceRemove bool
+
+	var worktreeRemoveCmd = &cobra.Command{
+		Use:     "remove [path]",
+		Short:   "(rm) Remove a worktree",
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.RemoveWorktree(args[0], worktreeForceRemove)
+		},
+	}
+	worktreeRemoveCmd.Flags().BoolVarP(&worktreeForceRemove, "force", "f", false, "Remove the worktree even if it has uncommitted changes")
+
+	var worktreePruneCmd = &cobra.Command{
+		Use:   "prune",
+		Short: "Remove administrative data of worktrees that no longer exist",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.PruneWorktrees()
+		},
+	}
+
+	worktreeCmd.AddCommand(
+		worktreeListCmd,
+		worktreeAddCmd,
+		worktreeRemoveCmd,
+		worktreePruneCmd,
+	)
+
+	var submoduleCmd = &cobra.Command{
+		Use:     "submodule",
+		Short:   "(sm) List and manage submodules",
+		Aliases: []string{"sm"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.ListSubmodules()
+		},
+	}
+
+	var submoduleListCmd = &cobra.Command{
+		Use:     "list",
+		Short:   "(ls) List submodules with their recorded and checked-out commits",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.ListSubmodules()
+		},
+	}
+
+	var submoduleInitCmd = &cobra.Command{
+		Use:   "init [paths]",
+		Short: "Register submodules in the local configuration",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.InitSubmodules(args)
+		},
+	}
+
+	var submoduleUpdateRemote bool
+
+	var submoduleUpdateCmd = &cobra.Command{
+		Use:   "update [paths]",
+		Short: "Check out the recorded commits of submodules, initializing them if needed",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.UpdateSubmodules(args, submoduleUpdateRemote)
+		},
+	}
+	submoduleUpdateCmd.Flags().BoolVar(&submoduleUpdateRemote, "remote", false, "Update to the latest commit of the tracked remote branch instead")
+
+	var submoduleSyncCmd = &cobra.Command{
+		Use:   "sync [paths]",
+		Short: "Copy submodule URLs from .gitmodules into the local configuration",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.SyncSubmodules(args)
+		},
+	}
+
+	var submoduleAddCmd = &cobra.Command{
+		Use:   "add [repository] [path]",
+		Short: "Add a repository as a submodule",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return git.AddSubmodule(args[0], "")
+			}
+			return git.AddSubmodule(args[0], args[1])
+		},
+	}
+
+	var submoduleDeinitForce bool
+
+	var submoduleDeinitCmd = &cobra.Command{
+		Use:   "deinit [path]",
+		Short: "Unregister a submodule and remove its working tree",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.DeinitSubmodule(args[0], submoduleDeinitForce)
+		},
+	}
+	submoduleDeinitCmd.Flags().BoolVarP(&submoduleDeinitForce, "force", "f", false, "Deinitialize even if the submodule has local modifications")
+
+	submoduleCmd.AddCommand(
+		submoduleListCmd,
+		submoduleInitCmd,
+		submoduleUpdateCmd,
+		submoduleSyncCmd,
+		submoduleAddCmd,
+		submoduleDeinitCmd,
+	)
+
+	var stashCmd = &cobra.Command{
+		Use:   "stash [message]",
+		Short: "Set aside uncommitted changes for later",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.StashChanges(strings.Join(args, " "))
+		},
+	}
+
+	var stashPopCmd = &cobra.Command{
+		Use:   "pop",
+		Short: "Re-apply the latest stashed changes and drop them from the stash",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return git.PopStash()
+		},
+	}
+
+	stashCmd.AddCommand(stashPopCmd)
+
+	var resetSoft, resetHard bool
+
+	var resetCmd = &cobra.Command{
+		Use:   "reset [commit]",
+		Short: "Move the current branch to another commit (default: HEAD)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := "HEAD"
+			if len(args) == 1 {
+				target = args[0]
+			}
+
+			mode := "mixed"
+			if resetSoft {
+				mode = "soft"
+			}
+			if resetHard {
+				mode = "hard"
+			}
+
+			return git.ResetToCommit(target, mode)
+		},
+	}
+	resetCmd.Flags().BoolVar(&resetSoft, "soft", false, "Keep the changes staged")
+	resetCmd.Flags().BoolVar(&resetHard, "hard", false, "Discard all changes in the staging area and working directory")
+	resetCmd.MarkFlagsMutuallyExclusive("soft", "hard")
+
+	var undoList bool
+
+	var undoCmd = &cobra.Command{
+		Use:   "undo",
+		Short: "Reverse the most recent operation done with igitt",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if undoList {
+				return git.ListUndo()
 			}
-			git.DoCustomBranchAction(strings.Join(args, " "))
+			return git.Undo()
 		},
 	}
+	undoCmd.Flags().BoolVarP(&undoList, "list", "l", false, "Show recent operations and what undoing each one would do")
+
 	var interactiveCmd = &cobra.Command{
 		Use:     "interactive",
 		Short:   "(i) Enter interactive mode",
 		Aliases: []string{"i"},
-		Run: func(cmd *cobra.Command, args []string) {
-			interactive.StartInteractive()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return interactive.StartInteractive()
 		},
 	}
 
+	var runParameters map[string]string
+
+	var runCmd = &cobra.Command{
+		Use:   "run [id]",
+		Short: "Run a custom command from the configuration without the menu",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return interactive.RunCustomCommand(args[0], runParameters)
+		},
+	}
+	runCmd.Flags().StringToStringVarP(&runParameters, "param", "p", nil, "The value of a prompt as name=value, prompts left out take their default")
+
 	var createAliasScripts = &cobra.Command{
 		Use:     "mkalias",
-		Short:   "Create scripts to use igt as an alias for igitt",
+		Short:   "Create the igt alias next to the executable, see igitt alias for more options",
 		Aliases: []string{"igt"},
-		Run: func(cmd *cobra.Command, args []string) {
-			operations.CreateAliasScripts()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operations.CreateAliasScripts()
+		},
+	}
+
+	var doctorFix bool
+
+	var doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Check Git, the configuration and the terminal for problems",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operations.RunDoctor(doctorFix)
 		},
 	}
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair the problems that can be fixed automatically")
+
+	var configShowOrigin bool
 
 	var igittConfigCmd = &cobra.Command{
 		Use:   "config",
 		Short: "Print the path of the igitt configuration file",
-		Run: func(cmd *cobra.Command, args []string) {
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if configShowOrigin {
+				return config.PrintConfigOrigins()
+			}
 			config.GetConfigPath(true)
+			return nil
+		},
+	}
+	igittConfigCmd.Flags().BoolVar(&configShowOrigin, "show-origin", false, "Show every effective setting and the file it comes from")
+
+	var configGetCmd = &cobra.Command{
+		Use:   "get [key]",
+		Short: "Print the effective value of a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := config.GetValue(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(value)
+			return nil
+		},
+	}
+
+	var configSetRepo bool
+
+	var configSetCmd = &cobra.Command{
+		Use:   "set [key] [value]",
+		Short: "Change a setting, keeping the comments in the configuration file",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := config.GetConfigPath(false)
+			if configSetRepo {
+				repoRoot := utilities.GetRepoRoot()
+				if repoRoot == "" {
+					return errors.New(locale.Get("config.repoOutside"))
+				}
+				configPath = config.GetRepoConfigFile(repoRoot)
+			}
+
+			value, err := config.SetValue(configPath, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(locale.Get("config.set", args[0], color.HiGreenString(value), color.BlueString(configPath)))
+			return nil
+		},
+	}
+	configSetCmd.Flags().BoolVar(&configSetRepo, "repo", false, "Write to the .igitt.yaml of the current repository instead")
+
+	var configListCmd = &cobra.Command{
+		Use:     "list",
+		Short:   "(ls) Print all effective settings",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return config.PrintConfigValues()
+		},
+	}
+
+	var configValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration files for unknown keys and invalid values",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return config.ValidateConfig()
+		},
+	}
+
+	var configTrustRemove bool
+
+	var configTrustCmd = &cobra.Command{
+		Use:   "trust",
+		Short: "Use the commands and shortcuts of the current repository's .igitt.yaml",
+		Long: `Use the commands and shortcuts of the current repository's .igitt.yaml.
+
+Commands in a repository's configuration run with your permissions, so igitt
+ignores its commands and shortcuts until you trust it. trust shows what they
+run and asks first. A changed .igitt.yaml has to be trusted again.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operations.TrustRepository(configTrustRemove)
 		},
 	}
+	configTrustCmd.Flags().BoolVar(&configTrustRemove, "remove", false, "Ignore the commands and shortcuts of the repository again")
+
+	igittConfigCmd.AddCommand(
+		configGetCmd,
+		configSetCmd,
+		configListCmd,
+		configValidateCmd,
+		configTrustCmd,
+	)
+
+	discoveredPlugins := plugins.Discover()
+
+	var pluginsCmd = &cobra.Command{
+		Use:   "plugins",
+		Short: "List the igitt-<name> plugins found in the plugins directory and on the PATH",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plugins.PrintPlugins(discoveredPlugins)
+			return nil
+		},
+	}
+
+	rootCmd.PersistentFlags().BoolVar(&git.DryRun, "dry-run", false, "Print the git commands that would run instead of changing anything")
+	rootCmd.PersistentFlags().BoolVar(&git.Explain, "explain", false, "Print and explain the git command behind every operation")
+	rootCmd.PersistentFlags().BoolVar(&utilities.ShowRawGitErrors, "raw-errors", false, "Show Git's original message below explained errors")
+	rootCmd.PersistentFlags().BoolVarP(&utilities.AssumeYes, "yes", "y", false, "Answer yes to every confirmation, for scripts and CI")
+	rootCmd.PersistentFlags().BoolVar(&plain.Enabled, "plain", false, "Print plain text without colors, spinners or decorative glyphs and ask one question per line, for screen readers and logs")
+
+	var logOptions logger.Options
+
+	rootCmd.PersistentFlags().StringVar(&logOptions.Level, "log-level", "info", "Only log messages of this level or above: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logOptions.File, "log-file", "", "Write the log to this file instead of the igitt state directory")
+	rootCmd.PersistentFlags().StringVar(&logOptions.Format, "log-format", "text", "Write the log as text or json")
 
 	cobra.OnInitialize(func() {
-		createdNewConfig, err := config.InitialConfig()
+		loggingErr := logger.Configure(logOptions)
+		plain.Configure(plain.Enabled)
 
-		if err != nil {
-			logger.ErrorLogger.Fatal(err)
+		// the shell reads everything printed as a suggestion, the config
+		// is not needed to complete anything
+		if isCompletionRequest() {
 			return
 		}
 
-		if len(os.Args) == 1 {
-			if createdNewConfig {
-				welcome.PrintWelcomeMessage()
+		if loggingErr != nil {
+			fmt.Println(color.HiYellowString("Logging: " + strings.ReplaceAll(loggingErr.Error(), "\n", "; ")))
+		}
+
+		var igittConfig config.IgittConfig
+
+		// config and doctor are how a broken configuration gets fixed, they
+		// run with the defaults instead and leave the file as it is
+		if isRepairCommand(rootCmd) {
+			var err error
+			igittConfig, err = config.LoadConfig()
+			if err != nil {
+				logger.WarningLogger.Println("Running with the default configuration:", err)
+			}
+		} else {
+			var err error
+			createdNewConfig, err = config.InitialConfig()
+
+			if err != nil {
+				logger.ErrorLogger.Fatal(err)
 				return
 			}
-			interactive.StartInteractive()
+
+			igittConfig = config.GetConfig()
+		}
+
+		locale.Load(igittConfig.Language)
+
+		if igittConfig.ExplainCommands {
+			git.Explain = true
+		}
+
+		if igittConfig.RawGitErrors {
+			utilities.ShowRawGitErrors = true
+		}
+
+		if igittConfig.Plain {
+			plain.Configure(true)
+		}
+
+		if err := theme.Load(igittConfig.Theme, config.GetCustomThemePath(igittConfig)); err != nil {
+			logger.ErrorLogger.Println("Failed to load theme:", err)
+			utilities.PrintGeneralError(locale.Get("theme.loadFailed", err))
 		}
 	})
 
@@ -165,6 +683,7 @@ func InitializeIgitt(version string, commit string, buildDate string) {
 		cloneCmd,
 		initCmd,
 		interactiveCmd,
+		runCmd,
 		gitAddCmd,
 		pullCmd,
 		pushCmd,
@@ -172,11 +691,32 @@ func InitializeIgitt(version string, commit string, buildDate string) {
 		checkoutCmd,
 		commitCmd,
 		createAliasScripts,
+		newAliasCommand(),
+		doctorCmd,
 		branchCmd,
+		worktreeCmd,
+		submoduleCmd,
+		stashCmd,
+		resetCmd,
+		undoCmd,
 		igittConfigCmd,
+		pluginsCmd,
+		newWorkspaceCommand(),
+		newCompletionCommand(rootCmd),
 	)
-	err := rootCmd.Execute()
+	interactive.Plugins = addPluginCommands(rootCmd, discoveredPlugins)
+
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
+	trackCommandStart(rootCmd)
+
+	command, err := rootCmd.ExecuteC()
 	if err != nil {
-		logger.ErrorLogger.Fatal(err)
+		logger.ErrorLogger.Println(err)
+
+		exitCode := getExitCode(err)
+		printError(command, err, exitCode)
+		os.Exit(exitCode)
 	}
 }
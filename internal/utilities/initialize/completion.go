@@ -0,0 +1,83 @@
+package initialize
+
+import (
+	"os"
+	"slices"
+
+	"github.com/nstr-dev/igitt/internal/operations/git"
+	"github.com/spf13/cobra"
+)
+
+const completionLong = `Generate the autocompletion script for igitt for the given shell.
+
+Bash, needs the bash-completion package:
+  igitt completion bash > ~/.local/share/bash-completion/completions/igitt
+
+Zsh, if completion is not enabled yet, add "autoload -U compinit; compinit" to ~/.zshrc:
+  igitt completion zsh > "${fpath[1]}/_igitt"
+
+Fish:
+  igitt completion fish > ~/.config/fish/completions/igitt.fish
+
+PowerShell, add this line to your $PROFILE:
+  igitt completion powershell | Out-String | Invoke-Expression
+
+Start a new shell afterwards.`
+
+func newCompletionCommand(rootCmd *cobra.Command) *cobra.Command {
+	return &cobra.Command{
+		Use:       "completion [bash|zsh|fish|powershell]",
+		Short:     "Generate the autocompletion script for your shell",
+		Long:      completionLong,
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch args[0] {
+			case "bash":
+				return rootCmd.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return rootCmd.GenZshCompletion(os.Stdout)
+			case "fish":
+				return rootCmd.GenFishCompletion(os.Stdout, true)
+			}
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		},
+	}
+}
+
+// isCompletionRequest tells whether the shell asks for suggestions or a
+// completion script, then nothing but that may be printed.
+func isCompletionRequest() bool {
+	if len(os.Args) < 2 {
+		return false
+	}
+
+	return slices.Contains([]string{cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd, "completion"}, os.Args[1])
+}
+
+func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return git.CompleteBranches(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeModifiedFiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions := git.CompleteModifiedFiles(args)
+	if completions == nil {
+		// outside of a repository or without changes, paths still work
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeRemotes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return git.CompleteRemotes(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeDirectories(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
@@ -0,0 +1,100 @@
+package initialize
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/operations/git"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/plugins"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes are part of igitt's interface for scripts, see the "Exit codes"
+// section of the README before changing them.
+const (
+	ExitOk             = 0
+	ExitError          = 1
+	ExitUsage          = 2
+	ExitNotARepository = 3
+	ExitGitFailed      = 4
+	ExitRefused        = 5
+	ExitGitMissing     = 6
+)
+
+// commandStarted tells errors of a command apart from errors cobra raises
+// while parsing the command line, which are usage errors.
+var commandStarted bool
+
+func trackCommandStart(command *cobra.Command) {
+	if command.RunE != nil {
+		runE := command.RunE
+		command.RunE = func(cmd *cobra.Command, args []string) error {
+			commandStarted = true
+			return runE(cmd, args)
+		}
+	}
+
+	for _, subCommand := range command.Commands() {
+		trackCommandStart(subCommand)
+	}
+}
+
+func getExitCode(err error) int {
+	if err == nil {
+		return ExitOk
+	}
+
+	// the plugin has reported the problem itself, igitt passes its code on
+	var pluginError *plugins.ExitError
+	if errors.As(err, &pluginError) {
+		return pluginError.ExitCode
+	}
+
+	if errors.Is(err, exec.ErrNotFound) {
+		return ExitGitMissing
+	}
+
+	var gitError *git.GitError
+	if errors.As(err, &gitError) {
+		if gitError.IsNotARepository() {
+			return ExitNotARepository
+		}
+		return ExitGitFailed
+	}
+
+	var refusedError *git.RefusedError
+	if errors.As(err, &refusedError) {
+		return ExitRefused
+	}
+
+	if !commandStarted {
+		return ExitUsage
+	}
+
+	return ExitError
+}
+
+// printError shows errors the operations did not already explain to the
+// user. Git and refusal errors are printed where they happen, plugins print
+// their own.
+func printError(command *cobra.Command, err error, exitCode int) {
+	var pluginError *plugins.ExitError
+	if errors.As(err, &pluginError) {
+		return
+	}
+
+	switch exitCode {
+	case ExitNotARepository, ExitGitFailed, ExitRefused:
+		return
+	case ExitGitMissing:
+		fmt.Println(color.HiRedString(locale.Get("error.gitMissing")))
+	case ExitUsage:
+		fmt.Println(color.HiRedString(locale.Get("plain.error") + " " + err.Error()))
+		fmt.Println(locale.Get("error.usageHint", command.CommandPath()))
+	default:
+		fmt.Println(color.HiRedString(locale.Get("plain.error") + " " + err.Error()))
+	}
+}
@@ -0,0 +1,60 @@
+package initialize
+
+import (
+	"os"
+	"slices"
+
+	"github.com/nstr-dev/igitt/internal/operations/git"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plugins"
+	"github.com/spf13/cobra"
+)
+
+// addPluginCommands registers every plugin as a subcommand. Built-in
+// commands and their aliases always win over a plugin with the same name.
+// It returns the plugins that were added.
+func addPluginCommands(rootCmd *cobra.Command, discovered []plugins.Plugin) []plugins.Plugin {
+	var added []plugins.Plugin
+
+	for i := range discovered {
+		plugin := discovered[i]
+
+		if existing, _, err := rootCmd.Find([]string{plugin.Name}); err == nil && existing != rootCmd {
+			logger.WarningLogger.Printf("Plugin %s at %s is hidden by the built-in command %s", plugin.Name, plugin.Path, existing.Name())
+			discovered[i].HiddenBy = existing.Name()
+			continue
+		}
+
+		added = append(added, plugin)
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                plugin.Name,
+			Short:              "(plugin) " + plugin.GetDescription(),
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				arguments, err := parseLeadingFlags(cmd, args)
+				if err != nil {
+					return err
+				}
+				return plugin.Run(arguments, git.DryRun)
+			},
+		})
+	}
+
+	return added
+}
+
+// parseLeadingFlags applies the igitt flags given in front of the plugin
+// name, e.g. igitt --dry-run review. Flag parsing is off for plugins, so
+// cobra passes them on together with the plugin's own arguments.
+func parseLeadingFlags(cmd *cobra.Command, args []string) ([]string, error) {
+	index := slices.Index(os.Args[1:], cmd.Name())
+	if index <= 0 || index > len(args) || !slices.Equal(args[:index], os.Args[1:index+1]) {
+		return args, nil
+	}
+
+	if err := cmd.Root().PersistentFlags().Parse(args[:index]); err != nil {
+		return nil, err
+	}
+
+	return args[index:], nil
+}
@@ -0,0 +1,155 @@
+package initialize
+
+import (
+	"github.com/nstr-dev/igitt/internal/operations"
+	"github.com/nstr-dev/igitt/internal/operations/git"
+	"github.com/nstr-dev/igitt/internal/utilities/config"
+	"github.com/spf13/cobra"
+)
+
+const workspaceLong = `Run status, fetch, pull or a command in many repositories at once.
+
+Without a directory igitt works on the repositories saved with
+igitt workspace add, or on the repositories below the current directory if
+none are saved. Given a directory, it works on the repositories below it.
+Repositories inside other repositories, hidden directories and node_modules
+are left out.
+
+The repositories are worked on at the same time, at most --jobs of them
+(workspaceJobs in the configuration, 8 by default). The results come back as
+a table with the branch, the staged, unstaged and untracked files, the commits
+ahead and behind the upstream and what failed in each repository.
+
+  igitt workspace status ~/services
+  igitt workspace pull --jobs 4
+  igitt workspace exec -- git log -1 --oneline
+  igitt workspace exec --path ~/services -- make test`
+
+func newWorkspaceCommand() *cobra.Command {
+	var jobs int
+
+	getJobs := func() int {
+		if jobs > 0 {
+			return jobs
+		}
+		return config.GetConfig().WorkspaceJobs
+	}
+
+	getDirectory := func(args []string) string {
+		if len(args) > 0 {
+			return args[0]
+		}
+		return ""
+	}
+
+	runStatus := func(cmd *cobra.Command, args []string) error {
+		repositories, err := operations.GetWorkspaceRepositories(getDirectory(args))
+		if err != nil {
+			return err
+		}
+		return git.WorkspaceStatus(repositories, getJobs())
+	}
+
+	var workspaceCmd = &cobra.Command{
+		Use:               "workspace [directory]",
+		Short:             "Run status, fetch, pull or a command in many repositories at once",
+		Long:              workspaceLong,
+		Aliases:           []string{"ws"},
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeDirectories,
+		RunE:              runStatus,
+	}
+	workspaceCmd.PersistentFlags().IntVarP(&jobs, "jobs", "j", 0, "How many repositories to work on at the same time (default: workspaceJobs from the configuration)")
+
+	var statusCmd = &cobra.Command{
+		Use:               "status [directory]",
+		Short:             "Show branch, changes and ahead and behind counts of every repository",
+		Aliases:           []string{"s"},
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeDirectories,
+		RunE:              runStatus,
+	}
+
+	var fetchCmd = &cobra.Command{
+		Use:               "fetch [directory]",
+		Short:             "Fetch every repository from its remote",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeDirectories,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repositories, err := operations.GetWorkspaceRepositories(getDirectory(args))
+			if err != nil {
+				return err
+			}
+			return git.WorkspaceFetch(repositories, getJobs())
+		},
+	}
+
+	var pullCmd = &cobra.Command{
+		Use:               "pull [directory]",
+		Short:             "Pull every repository, only where it can be fast-forwarded",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeDirectories,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repositories, err := operations.GetWorkspaceRepositories(getDirectory(args))
+			if err != nil {
+				return err
+			}
+			return git.WorkspacePull(repositories, getJobs())
+		},
+	}
+
+	var execPath string
+
+	var execCmd = &cobra.Command{
+		Use:   "exec [--path directory] -- command [arguments...]",
+		Short: "Run a command in every repository",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repositories, err := operations.GetWorkspaceRepositories(execPath)
+			if err != nil {
+				return err
+			}
+			return git.WorkspaceExec(repositories, getJobs(), args)
+		},
+	}
+	execCmd.Flags().StringVar(&execPath, "path", "", "Search this directory for repositories instead of using the saved workspace")
+	execCmd.Flags().SetInterspersed(false)
+	execCmd.MarkFlagDirname("path")
+
+	var addCmd = &cobra.Command{
+		Use:               "add [directory...]",
+		Short:             "Save the repositories at or below the directories in the workspace",
+		ValidArgsFunction: completeDirectories,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+			return operations.AddToWorkspace(args)
+		},
+	}
+
+	var removeCmd = &cobra.Command{
+		Use:               "remove directory...",
+		Short:             "Remove repositories from the saved workspace",
+		Aliases:           []string{"rm"},
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeDirectories,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operations.RemoveFromWorkspace(args)
+		},
+	}
+
+	var listCmd = &cobra.Command{
+		Use:     "list",
+		Short:   "List the repositories of the saved workspace",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operations.PrintWorkspace()
+		},
+	}
+
+	workspaceCmd.AddCommand(statusCmd, fetchCmd, pullCmd, execCmd, addCmd, removeCmd, listCmd)
+
+	return workspaceCmd
+}
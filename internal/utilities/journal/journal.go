@@ -0,0 +1,510 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+)
+
+var ErrNothingToUndo = errors.New("there is nothing to undo")
+
+// ErrIndexNotRecorded is returned for entries that need the staging area of
+// before the operation, which could not be recorded, e.g. during a merge
+// conflict.
+var ErrIndexNotRecorded = errors.New("the staging area before the operation was not recorded")
+
+// ErrUntrackedFiles is returned for entries whose undo would restore the
+// tracked files only, while the operation also added, removed or overwrote
+// untracked files or created files undo would leave behind.
+var ErrUntrackedFiles = errors.New("the operation changed untracked files, which undo cannot restore")
+
+const journalFileName = "igitt-journal.json"
+const maxEntries = 50
+
+const (
+	OpAdd          = "add"
+	OpCommit       = "commit"
+	OpCheckout     = "checkout"
+	OpCreateBranch = "create-branch"
+	OpDeleteBranch = "delete-branch"
+	OpRenameBranch = "rename-branch"
+	OpPull         = "pull"
+	OpReset        = "reset"
+	OpStash        = "stash"
+	OpStashPop     = "stash-pop"
+)
+
+// State is a snapshot of everything an undo needs to put back: where HEAD
+// points, the staged tree, the tracked working tree and all local refs. The
+// untracked files are only listed, their content is not recorded.
+type State struct {
+	Head      string            `json:"head"`
+	Branch    string            `json:"branch"`
+	Index     string            `json:"index"`
+	Worktree  string            `json:"worktree"`
+	Untracked []string          `json:"untracked,omitempty"`
+	Refs      map[string]string `json:"refs"`
+}
+
+type Entry struct {
+	Id          int       `json:"id"`
+	Operation   string    `json:"operation"`
+	Description string    `json:"description"`
+	Detail      string    `json:"detail,omitempty"`
+	Time        time.Time `json:"time"`
+	Before      State     `json:"before"`
+	After       State     `json:"after"`
+	Undone      bool      `json:"undone"`
+}
+
+type Step struct {
+	Description string
+	Arguments   []string
+}
+
+func git(arguments ...string) (string, error) {
+	byteOut, errOut := exec.Command("git", arguments...).Output()
+	return strings.TrimSpace(string(byteOut)), errOut
+}
+
+func getJournalPath() (string, error) {
+	gitDir, err := git("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, journalFileName), nil
+}
+
+func CaptureState() State {
+	state := State{Refs: make(map[string]string)}
+
+	state.Head, _ = git("rev-parse", "--verify", "--quiet", "HEAD")
+	state.Branch, _ = git("symbolic-ref", "--quiet", "--short", "HEAD")
+
+	// write-tree fails while there are unmerged entries, an empty index tree
+	// simply means undo will not touch the staging area
+	state.Index, _ = git("write-tree")
+
+	stashCommit, err := git("stash", "create")
+	if err == nil && stashCommit != "" {
+		state.Worktree, _ = git("rev-parse", stashCommit+"^{tree}")
+	} else if state.Head != "" {
+		state.Worktree, _ = git("rev-parse", state.Head+"^{tree}")
+	}
+
+	// stash create leaves out untracked files, ":/" lists them in the whole
+	// working tree and not only below the current directory
+	state.Untracked = getPaths("ls-files", "--others", "--exclude-standard", "--full-name", "-z", ":/")
+
+	refs, err := git("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads", "refs/stash")
+	if err == nil && refs != "" {
+		for _, line := range strings.Split(refs, "\n") {
+			ref, object, found := strings.Cut(line, " ")
+			if found {
+				state.Refs[ref] = object
+			}
+		}
+	}
+
+	return state
+}
+
+// getPaths runs a git command that lists paths separated by NUL bytes.
+func getPaths(arguments ...string) []string {
+	output, err := git(arguments...)
+	if err != nil {
+		return nil
+	}
+
+	return slices.DeleteFunc(strings.Split(output, "\x00"), func(path string) bool {
+		return path == ""
+	})
+}
+
+func ReadEntries() ([]Entry, error) {
+	journalPath, err := getJournalPath()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(journalPath)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	err = json.Unmarshal(content, &entries)
+	return entries, err
+}
+
+func writeEntries(entries []Entry) error {
+	journalPath, err := getJournalPath()
+	if err != nil {
+		return err
+	}
+
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(journalPath, content, 0644)
+}
+
+// Record stores an operation that went from before to the current state.
+// Failing to journal must never fail the operation itself, so errors are
+// only logged.
+func Record(operation string, description string, detail string, before State) {
+	after := CaptureState()
+
+	if statesEqual(before, after) {
+		logger.InfoLogger.Println("Operation did not change the repository, not journaling:", operation)
+		return
+	}
+
+	entries, err := ReadEntries()
+	if err != nil {
+		logger.ErrorLogger.Println("Failed to read journal:", err)
+		entries = []Entry{}
+	}
+
+	id := 1
+	if len(entries) > 0 {
+		id = entries[len(entries)-1].Id + 1
+	}
+
+	entries = append(entries, Entry{
+		Id:          id,
+		Operation:   operation,
+		Description: description,
+		Detail:      detail,
+		Time:        time.Now(),
+		Before:      before,
+		After:       after,
+	})
+
+	if err := writeEntries(entries); err != nil {
+		logger.ErrorLogger.Println("Failed to write journal:", err)
+		return
+	}
+
+	logger.InfoLogger.Printf("Journaled %s operation as entry %d", operation, id)
+}
+
+func MarkUndone(id int) error {
+	entries, err := ReadEntries()
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		if entries[i].Id == id {
+			entries[i].Undone = true
+		}
+	}
+
+	return writeEntries(entries)
+}
+
+func GetLastUndoable() (Entry, error) {
+	entries, err := ReadEntries()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].Undone {
+			return entries[i], nil
+		}
+	}
+
+	return Entry{}, ErrNothingToUndo
+}
+
+func statesEqual(a State, b State) bool {
+	if a.Head != b.Head || a.Branch != b.Branch || a.Index != b.Index || a.Worktree != b.Worktree {
+		return false
+	}
+
+	if len(a.Refs) != len(b.Refs) {
+		return false
+	}
+
+	for ref, object := range a.Refs {
+		if b.Refs[ref] != object {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetChangesSince lists what changed in the repository after the entry was
+// recorded. Undoing is only safe while this list is empty.
+func GetChangesSince(entry Entry) []string {
+	current := CaptureState()
+
+	var changes []string
+
+	if current.Head != entry.After.Head || current.Branch != entry.After.Branch {
+		changes = append(changes, locale.Get("journal.headMoved"))
+	}
+	if current.Index != entry.After.Index {
+		changes = append(changes, locale.Get("journal.indexChanged"))
+	}
+	if current.Worktree != entry.After.Worktree {
+		changes = append(changes, locale.Get("journal.filesChanged"))
+	}
+
+	for ref, object := range entry.After.Refs {
+		if current.Refs[ref] != object {
+			changes = append(changes, describeRefChange(ref))
+		}
+	}
+	if _, existed := entry.After.Refs["refs/stash"]; !existed && current.Refs["refs/stash"] != "" {
+		changes = append(changes, locale.Get("journal.stashChanged"))
+	}
+
+	return changes
+}
+
+func describeRefChange(ref string) string {
+	if ref == "refs/stash" {
+		return locale.Get("journal.stashChanged")
+	}
+	return locale.Get("journal.branchChanged", strings.TrimPrefix(ref, "refs/heads/"))
+}
+
+func shortObject(object string) string {
+	if len(object) > 7 {
+		return object[:7]
+	}
+	return object
+}
+
+func checkoutPreviousHead(before State) Step {
+	if before.Branch != "" {
+		return Step{
+			Description: locale.Get("journal.switchBranch", before.Branch),
+			Arguments:   []string{"checkout", before.Branch},
+		}
+	}
+
+	return Step{
+		Description: locale.Get("journal.switchCommit", shortObject(before.Head)),
+		Arguments:   []string{"checkout", "--detach", before.Head},
+	}
+}
+
+// restoreIndex is empty if the staging area could not be recorded.
+func restoreIndex(before State, description string) []Step {
+	if before.Index == "" {
+		return nil
+	}
+
+	return []Step{{
+		Description: description,
+		Arguments:   []string{"read-tree", before.Index},
+	}}
+}
+
+func restoreFilesAndIndex(before State) []Step {
+	var steps []Step
+
+	if before.Worktree != "" {
+		// ":/" is the whole working tree, "." only what is below the current
+		// directory
+		steps = append(steps, Step{
+			Description: locale.Get("journal.restoreFiles"),
+			Arguments:   []string{"checkout", before.Worktree, "--", ":/"},
+		})
+	}
+
+	return append(steps, restoreIndex(before, locale.Get("journal.restoreIndex"))...)
+}
+
+// CheckUndoable tells whether PlanUndo can reverse the entry completely.
+func CheckUndoable(entry Entry) error {
+	if entry.Operation == OpAdd && entry.Before.Index == "" {
+		return ErrIndexNotRecorded
+	}
+
+	if entry.Operation == OpReset || entry.Operation == OpStashPop {
+		if len(getUntrackedChanges(entry.Before, entry.After)) > 0 {
+			return ErrUntrackedFiles
+		}
+	}
+
+	return nil
+}
+
+// getUntrackedChanges lists the files undo cannot put back: untracked files
+// the operation removed or overwrote and files it created, which checking
+// out the files of before would leave behind.
+func getUntrackedChanges(before State, after State) []string {
+	wasUntracked := make(map[string]bool)
+	for _, path := range before.Untracked {
+		wasUntracked[path] = true
+	}
+	isUntracked := make(map[string]bool)
+	for _, path := range after.Untracked {
+		isUntracked[path] = true
+	}
+
+	var changes, added []string
+	for _, path := range before.Untracked {
+		if !isUntracked[path] {
+			changes = append(changes, path)
+		}
+	}
+	for _, path := range after.Untracked {
+		if !wasUntracked[path] {
+			added = append(added, path)
+		}
+	}
+
+	// files that are untracked now but part of before, e.g. a new file a
+	// mixed reset unstaged, are checked out again
+	if len(added) > 0 && before.Worktree != "" {
+		restored := getPaths(append([]string{"ls-tree", "-r", "--full-tree", "--name-only", "-z", before.Worktree, "--"}, added...)...)
+		added = slices.DeleteFunc(added, func(path string) bool {
+			return slices.Contains(restored, path)
+		})
+	}
+
+	changes = append(changes, added...)
+	return append(changes, getCreatedFiles(before, after)...)
+}
+
+// getCreatedFiles lists the files the operation added to the working tree.
+// Checking out the files of before does not remove them, so an undo would
+// leave them behind as untracked files.
+func getCreatedFiles(before State, after State) []string {
+	if before.Worktree == "" || after.Worktree == "" || before.Worktree == after.Worktree {
+		return nil
+	}
+
+	return getPaths("diff", "--name-only", "--no-renames", "--diff-filter=A", "-z", before.Worktree, after.Worktree)
+}
+
+// PlanUndo translates an entry into the git commands that reverse it.
+func PlanUndo(entry Entry) []Step {
+	before := entry.Before
+	after := entry.After
+
+	switch entry.Operation {
+	case OpAdd:
+		return restoreIndex(before, locale.Get("journal.unstage"))
+
+	case OpCommit:
+		if before.Head == "" {
+			return []Step{{
+				Description: locale.Get("journal.removeFirstCommit", after.Branch),
+				Arguments:   []string{"update-ref", "-d", "refs/heads/" + after.Branch},
+			}}
+		}
+		return []Step{{
+			Description: locale.Get("journal.moveBackStaged", after.Branch, shortObject(before.Head)),
+			Arguments:   []string{"reset", "--soft", before.Head},
+		}}
+
+	case OpCheckout:
+		return []Step{checkoutPreviousHead(before)}
+
+	case OpCreateBranch:
+		steps := []Step{checkoutPreviousHead(before)}
+		for ref := range after.Refs {
+			if _, existed := before.Refs[ref]; !existed && strings.HasPrefix(ref, "refs/heads/") {
+				branch := strings.TrimPrefix(ref, "refs/heads/")
+				steps = append(steps, Step{
+					Description: locale.Get("journal.deleteBranch", branch),
+					Arguments:   []string{"branch", "-D", branch},
+				})
+			}
+		}
+		return steps
+
+	case OpDeleteBranch:
+		var steps []Step
+		for ref, object := range before.Refs {
+			if _, exists := after.Refs[ref]; !exists && strings.HasPrefix(ref, "refs/heads/") {
+				branch := strings.TrimPrefix(ref, "refs/heads/")
+				steps = append(steps, Step{
+					Description: locale.Get("journal.recreateBranch", branch, shortObject(object)),
+					Arguments:   []string{"branch", branch, object},
+				})
+			}
+		}
+		return steps
+
+	case OpRenameBranch:
+		var oldName, newName string
+		for ref := range before.Refs {
+			if _, exists := after.Refs[ref]; !exists {
+				oldName = strings.TrimPrefix(ref, "refs/heads/")
+			}
+		}
+		for ref := range after.Refs {
+			if _, existed := before.Refs[ref]; !existed {
+				newName = strings.TrimPrefix(ref, "refs/heads/")
+			}
+		}
+		if oldName == "" || newName == "" {
+			return nil
+		}
+		return []Step{{
+			Description: locale.Get("journal.renameBack", newName, oldName),
+			Arguments:   []string{"branch", "-m", newName, oldName},
+		}}
+
+	case OpPull:
+		if before.Head == after.Head {
+			return nil
+		}
+		return []Step{{
+			Description: locale.Get("journal.moveBackPulled", after.Branch, shortObject(before.Head)),
+			Arguments:   []string{"reset", "--keep", before.Head},
+		}}
+
+	case OpReset:
+		steps := []Step{{
+			Description: locale.Get("journal.moveBack", after.Branch, shortObject(before.Head)),
+			Arguments:   []string{"reset", "--soft", before.Head},
+		}}
+		if before.Worktree != after.Worktree {
+			return append(steps, restoreFilesAndIndex(before)...)
+		}
+		return append(steps, restoreIndex(before, locale.Get("journal.restoreIndex"))...)
+
+	case OpStash:
+		return []Step{{
+			Description: locale.Get("journal.reapplyStash"),
+			Arguments:   []string{"stash", "pop", "--index"},
+		}}
+
+	case OpStashPop:
+		stashCommit := before.Refs["refs/stash"]
+		steps := []Step{{
+			Description: locale.Get("journal.restash"),
+			Arguments:   []string{"stash", "store", "-m", entry.Detail, stashCommit},
+		}}
+		return append(steps, restoreFilesAndIndex(before)...)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package journal
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func getArguments(steps []Step) [][]string {
+	arguments := [][]string{}
+	for _, step := range steps {
+		arguments = append(arguments, step.Arguments)
+	}
+	return arguments
+}
+
+func TestPlanUndo(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		want  [][]string
+	}{
+		{
+			name: "add restores the index",
+			entry: Entry{
+				Operation: OpAdd,
+				Before:    State{Index: "index1"},
+				After:     State{Index: "index2"},
+			},
+			want: [][]string{{"read-tree", "index1"}},
+		},
+		{
+			name: "add without a recorded index has no steps",
+			entry: Entry{
+				Operation: OpAdd,
+				Before:    State{Index: ""},
+				After:     State{Index: "index2"},
+			},
+			want: [][]string{},
+		},
+		{
+			name: "commit moves the branch back",
+			entry: Entry{
+				Operation: OpCommit,
+				Before:    State{Head: "head1", Branch: "main"},
+				After:     State{Head: "head2", Branch: "main"},
+			},
+			want: [][]string{{"reset", "--soft", "head1"}},
+		},
+		{
+			name: "first commit deletes the branch",
+			entry: Entry{
+				Operation: OpCommit,
+				Before:    State{Branch: "main"},
+				After:     State{Head: "head1", Branch: "main"},
+			},
+			want: [][]string{{"update-ref", "-d", "refs/heads/main"}},
+		},
+		{
+			name: "checkout of a detached head",
+			entry: Entry{
+				Operation: OpCheckout,
+				Before:    State{Head: "head1"},
+				After:     State{Head: "head2", Branch: "main"},
+			},
+			want: [][]string{{"checkout", "--detach", "head1"}},
+		},
+		{
+			name: "create branch switches back and deletes it",
+			entry: Entry{
+				Operation: OpCreateBranch,
+				Before:    State{Branch: "main", Refs: map[string]string{"refs/heads/main": "head1"}},
+				After:     State{Branch: "feature", Refs: map[string]string{"refs/heads/main": "head1", "refs/heads/feature": "head1"}},
+			},
+			want: [][]string{{"checkout", "main"}, {"branch", "-D", "feature"}},
+		},
+		{
+			name: "delete branch recreates it",
+			entry: Entry{
+				Operation: OpDeleteBranch,
+				Before:    State{Refs: map[string]string{"refs/heads/main": "head1", "refs/heads/old": "head2"}},
+				After:     State{Refs: map[string]string{"refs/heads/main": "head1"}},
+			},
+			want: [][]string{{"branch", "old", "head2"}},
+		},
+		{
+			name: "rename branch renames it back",
+			entry: Entry{
+				Operation: OpRenameBranch,
+				Before:    State{Refs: map[string]string{"refs/heads/old": "head1"}},
+				After:     State{Refs: map[string]string{"refs/heads/new": "head1"}},
+			},
+			want: [][]string{{"branch", "-m", "new", "old"}},
+		},
+		{
+			name: "pull without new commits has no steps",
+			entry: Entry{
+				Operation: OpPull,
+				Before:    State{Head: "head1"},
+				After:     State{Head: "head1"},
+			},
+			want: [][]string{},
+		},
+		{
+			name: "hard reset restores the whole working tree and the index",
+			entry: Entry{
+				Operation: OpReset,
+				Before:    State{Head: "head1", Index: "index1", Worktree: "tree1"},
+				After:     State{Head: "head2", Branch: "main", Index: "index2", Worktree: "tree2"},
+			},
+			want: [][]string{{"reset", "--soft", "head1"}, {"checkout", "tree1", "--", ":/"}, {"read-tree", "index1"}},
+		},
+		{
+			name: "soft reset restores the index",
+			entry: Entry{
+				Operation: OpReset,
+				Before:    State{Head: "head1", Index: "index1", Worktree: "tree1"},
+				After:     State{Head: "head2", Branch: "main", Index: "index2", Worktree: "tree1"},
+			},
+			want: [][]string{{"reset", "--soft", "head1"}, {"read-tree", "index1"}},
+		},
+		{
+			name: "reset without a recorded index keeps the index",
+			entry: Entry{
+				Operation: OpReset,
+				Before:    State{Head: "head1", Worktree: "tree1"},
+				After:     State{Head: "head2", Branch: "main", Index: "index2", Worktree: "tree2"},
+			},
+			want: [][]string{{"reset", "--soft", "head1"}, {"checkout", "tree1", "--", ":/"}},
+		},
+		{
+			name: "stash pops it again",
+			entry: Entry{
+				Operation: OpStash,
+			},
+			want: [][]string{{"stash", "pop", "--index"}},
+		},
+		{
+			name: "stash pop stores it and restores the files",
+			entry: Entry{
+				Operation: OpStashPop,
+				Detail:    "WIP on main",
+				Before:    State{Index: "index1", Worktree: "tree1", Refs: map[string]string{"refs/stash": "stash1"}},
+			},
+			want: [][]string{{"stash", "store", "-m", "WIP on main", "stash1"}, {"checkout", "tree1", "--", ":/"}, {"read-tree", "index1"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := getArguments(PlanUndo(test.entry))
+			if !slices.EqualFunc(got, test.want, slices.Equal) {
+				t.Errorf("PlanUndo() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRestoreFilesAndIndex(t *testing.T) {
+	tests := []struct {
+		name   string
+		before State
+		want   [][]string
+	}{
+		{"files and index", State{Index: "index1", Worktree: "tree1"}, [][]string{{"checkout", "tree1", "--", ":/"}, {"read-tree", "index1"}}},
+		{"files only", State{Worktree: "tree1"}, [][]string{{"checkout", "tree1", "--", ":/"}}},
+		{"index only", State{Index: "index1"}, [][]string{{"read-tree", "index1"}}},
+		{"nothing recorded", State{}, [][]string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := getArguments(restoreFilesAndIndex(test.before))
+			if !slices.EqualFunc(got, test.want, slices.Equal) {
+				t.Errorf("restoreFilesAndIndex() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckUndoable(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		want  error
+	}{
+		{"add with index", Entry{Operation: OpAdd, Before: State{Index: "index1"}}, nil},
+		{"add without index", Entry{Operation: OpAdd}, ErrIndexNotRecorded},
+		{"reset without index", Entry{Operation: OpReset, Before: State{Head: "head1"}}, nil},
+		{"reset with the same untracked files", Entry{Operation: OpReset, Before: State{Untracked: []string{"notes"}}, After: State{Untracked: []string{"notes"}}}, nil},
+		{"reset that overwrote an untracked file", Entry{Operation: OpReset, Before: State{Untracked: []string{"notes"}}}, ErrUntrackedFiles},
+		{"stash pop that restored an untracked file", Entry{Operation: OpStashPop, After: State{Untracked: []string{"notes"}}}, ErrUntrackedFiles},
+		{"checkout ignores untracked files", Entry{Operation: OpCheckout, Before: State{Untracked: []string{"notes"}}}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CheckUndoable(test.entry); !errors.Is(got, test.want) {
+				t.Errorf("CheckUndoable() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
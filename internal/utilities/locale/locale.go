@@ -0,0 +1,161 @@
+package locale
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+)
+
+// Auto picks the language of the environment, see Detect.
+const Auto = "auto"
+
+// English is the fallback for unknown languages and for texts a catalog
+// does not translate.
+const English = "en"
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+var catalogs map[string]map[string]string
+var loadCatalogs sync.Once
+
+var language string
+
+func getCatalogs() map[string]map[string]string {
+	loadCatalogs.Do(func() {
+		catalogs = make(map[string]map[string]string)
+
+		files, err := catalogFiles.ReadDir("catalogs")
+		if err != nil {
+			logger.ErrorLogger.Println("Failed to read message catalogs:", err)
+			return
+		}
+
+		for _, file := range files {
+			content, err := catalogFiles.ReadFile(path.Join("catalogs", file.Name()))
+			if err != nil {
+				logger.ErrorLogger.Println("Failed to read message catalog:", file.Name(), err)
+				continue
+			}
+
+			var catalog map[string]string
+			if err := json.Unmarshal(content, &catalog); err != nil {
+				logger.ErrorLogger.Println("Invalid message catalog:", file.Name(), err)
+				continue
+			}
+
+			catalogs[strings.TrimSuffix(file.Name(), ".json")] = catalog
+		}
+	})
+
+	return catalogs
+}
+
+// Supported returns the languages igitt has a catalog for.
+func Supported() []string {
+	var languages []string
+	for language := range getCatalogs() {
+		languages = append(languages, language)
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// Detect reads the language from LC_ALL, LC_MESSAGES or LANG, e.g. de from
+// de_DE.UTF-8. Unset, C and POSIX locales mean English.
+func Detect() string {
+	for _, variable := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		fields := strings.FieldsFunc(os.Getenv(variable), func(r rune) bool {
+			return r == '_' || r == '-' || r == '.' || r == '@'
+		})
+		if len(fields) == 0 {
+			continue
+		}
+
+		value := strings.ToLower(fields[0])
+		if value == "c" || value == "posix" {
+			return English
+		}
+		return value
+	}
+
+	return English
+}
+
+// Load sets the language of all texts, Auto or an unknown language fall
+// back to the environment and then to English.
+func Load(configured string) {
+	configured = strings.ToLower(strings.TrimSpace(configured))
+
+	if configured == "" || configured == Auto {
+		configured = Detect()
+	}
+
+	if _, found := getCatalogs()[configured]; !found {
+		logger.InfoLogger.Printf("No message catalog for %q, using English", configured)
+		configured = English
+	}
+
+	language = configured
+	logger.DebugLogger.Println("Language:", language)
+}
+
+// Language returns the language the texts are shown in.
+func Language() string {
+	if language == "" {
+		Load(Auto)
+	}
+	return language
+}
+
+// Lookup returns the text for key in the current language only, without
+// falling back to English.
+func Lookup(key string) (string, bool) {
+	text, found := getCatalogs()[Language()][key]
+	return text, found
+}
+
+// Has tells whether there is a text for key in the current language or in
+// English.
+func Has(key string) bool {
+	if _, found := Lookup(key); found {
+		return true
+	}
+	_, found := getCatalogs()[English][key]
+	return found
+}
+
+// Get returns the text for key in the current language, formatted with
+// args like fmt.Sprintf. Missing texts fall back to English, then to the
+// key itself so a gap in a catalog never hides a message.
+func Get(key string, args ...any) string {
+	text, found := Lookup(key)
+	if !found {
+		text, found = getCatalogs()[English][key]
+	}
+	if !found {
+		logger.WarningLogger.Println("Missing text in message catalog:", key)
+		text = key
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Plural returns the text for key.one or key.other depending on count,
+// count is the first argument of the text.
+func Plural(count int, key string, args ...any) string {
+	if count == 1 {
+		return Get(key+".one", append([]any{count}, args...)...)
+	}
+	return Get(key+".other", append([]any{count}, args...)...)
+}
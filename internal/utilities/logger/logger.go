@@ -1,29 +1,227 @@
 package logger
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 )
 
+const logFileName = "igitt.log"
+
+var level = new(slog.LevelVar)
+var root = &deferredHandler{state: &deferredState{}}
+var discardHandler = slog.NewTextHandler(io.Discard, nil)
+
+var logPath string
+var logError error
+
+// The loggers keep the API of the standard log package for the existing
+// call sites, every line becomes a slog record with the matching level.
 var (
-	WarningLogger *log.Logger
-	InfoLogger    *log.Logger
-	ErrorLogger   *log.Logger
+	DebugLogger   = slog.NewLogLogger(root, slog.LevelDebug)
+	InfoLogger    = slog.NewLogLogger(root, slog.LevelInfo)
+	WarningLogger = slog.NewLogLogger(root, slog.LevelWarn)
+	ErrorLogger   = slog.NewLogLogger(root, slog.LevelError)
 )
 
-func init() {
-	executable, err := os.Executable()
-	if err != nil {
-		fmt.Println("Error getting executable path")
+type Options struct {
+	Level  string
+	Format string
+	File   string
+}
+
+// GetDefaultLogPath follows the XDG base directory specification, logs are
+// state that should survive restarts but is not worth backing up.
+func GetDefaultLogPath() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+
+	if stateHome == "" && runtime.GOOS == "windows" {
+		localAppData, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(localAppData, "igitt", logFileName), nil
+	}
+
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(stateHome, "igitt", logFileName), nil
+}
+
+func parseLevel(name string) (slog.Level, error) {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(name)); err != nil {
+		return parsed, fmt.Errorf("invalid log level %q, expected debug, info, warn or error", name)
+	}
+	return parsed, nil
+}
+
+// Configure opens the log file and writes the lines logged before it was
+// called. Until then nothing touches the disk, so importing this package has
+// no side effects. An invalid level or format falls back to the default, if
+// the file cannot be opened logging is turned off.
+func Configure(options Options) error {
+	var problems []error
+
+	if options.Level != "" {
+		parsedLevel, err := parseLevel(options.Level)
+		if err != nil {
+			problems = append(problems, err)
+		} else {
+			level.Set(parsedLevel)
+		}
+	}
+
+	path := options.File
+	if path == "" {
+		defaultPath, err := GetDefaultLogPath()
+		if err != nil {
+			logError = err
+			root.state.activate(discardHandler)
+			return errors.Join(append(problems, err)...)
+		}
+		path = defaultPath
 	}
+	logPath = path
 
-	file, err := os.OpenFile(executable+".log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	writer, err := openRotatingFile(path)
 	if err != nil {
-		log.Fatal(err)
+		logError = err
+		root.state.activate(discardHandler)
+		return errors.Join(append(problems, fmt.Errorf("cannot write the log file: %w", err))...)
+	}
+
+	handlerOptions := &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       level,
+		ReplaceAttr: shortenSource,
+	}
+
+	switch strings.ToLower(options.Format) {
+	case "json":
+		root.state.activate(slog.NewJSONHandler(writer, handlerOptions))
+	case "", "text":
+		root.state.activate(slog.NewTextHandler(writer, handlerOptions))
+	default:
+		problems = append(problems, fmt.Errorf("invalid log format %q, expected text or json", options.Format))
+		root.state.activate(slog.NewTextHandler(writer, handlerOptions))
+	}
+
+	return errors.Join(problems...)
+}
+
+// GetLogPath returns the file chosen by Configure and the error that kept
+// it from being opened, if any.
+func GetLogPath() (string, error) {
+	return logPath, logError
+}
+
+// shortenSource logs file:line like log.Lshortfile did.
+func shortenSource(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Key != slog.SourceKey || len(groups) > 0 {
+		return attr
+	}
+
+	source, ok := attr.Value.Any().(*slog.Source)
+	if !ok {
+		return attr
+	}
+
+	return slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", filepath.Base(source.File), source.Line))
+}
+
+// maxPendingRecords caps the lines kept in memory while the log file is not
+// opened yet, e.g. for commands that end before Configure runs.
+const maxPendingRecords = 500
+
+type pendingRecord struct {
+	handler *deferredHandler
+	record  slog.Record
+}
+
+type deferredState struct {
+	mutex   sync.Mutex
+	target  slog.Handler
+	pending []pendingRecord
+}
+
+func (s *deferredState) activate(target slog.Handler) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.target = target
+
+	for _, pending := range s.pending {
+		handler := pending.handler.resolve(target)
+		if handler.Enabled(context.Background(), pending.record.Level) {
+			handler.Handle(context.Background(), pending.record)
+		}
+	}
+	s.pending = nil
+}
+
+// deferredHandler keeps records in memory until Configure picked the file
+// and format, then passes them on.
+type deferredHandler struct {
+	state *deferredState
+	// wraps are the WithAttrs and WithGroup calls to repeat on the target
+	wraps []func(slog.Handler) slog.Handler
+}
+
+func (h *deferredHandler) resolve(target slog.Handler) slog.Handler {
+	for _, wrap := range h.wraps {
+		target = wrap(target)
+	}
+	return target
+}
+
+func (h *deferredHandler) Enabled(_ context.Context, recordLevel slog.Level) bool {
+	return recordLevel >= level.Level()
+}
+
+func (h *deferredHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.state.mutex.Lock()
+
+	if h.state.target == nil {
+		if len(h.state.pending) < maxPendingRecords {
+			h.state.pending = append(h.state.pending, pendingRecord{h, record.Clone()})
+		}
+		h.state.mutex.Unlock()
+		return nil
 	}
 
-	InfoLogger = log.New(file, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
-	WarningLogger = log.New(file, "WARNING: ", log.Ldate|log.Ltime|log.Lshortfile)
-	ErrorLogger = log.New(file, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+	target := h.resolve(h.state.target)
+	h.state.mutex.Unlock()
+
+	return target.Handle(ctx, record)
+}
+
+func (h *deferredHandler) with(wrap func(slog.Handler) slog.Handler) *deferredHandler {
+	wraps := append(append([]func(slog.Handler) slog.Handler{}, h.wraps...), wrap)
+	return &deferredHandler{state: h.state, wraps: wraps}
+}
+
+func (h *deferredHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(target slog.Handler) slog.Handler {
+		return target.WithAttrs(attrs)
+	})
+}
+
+func (h *deferredHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(target slog.Handler) slog.Handler {
+		return target.WithGroup(name)
+	})
 }
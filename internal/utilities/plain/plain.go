@@ -0,0 +1,53 @@
+package plain
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+)
+
+// Enabled turns off colors, spinners and decorative glyphs, prints states as
+// labeled text and runs the forms in huh's accessible mode, for screen
+// readers and captured logs.
+var Enabled bool
+
+// Configure sets up the output once the flags and the configuration are
+// read. Colors are also turned off when NO_COLOR is set or the output is
+// not a terminal, see https://no-color.org.
+func Configure(enabled bool) {
+	Enabled = enabled
+
+	if enabled || os.Getenv("NO_COLOR") != "" || !IsTerminalOutput() {
+		color.NoColor = true
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// IsTerminalOutput tells whether the output goes to a terminal rather than
+// to a pipe or a file.
+func IsTerminalOutput() bool {
+	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+}
+
+// Label returns the glyph, or the text label in plain mode, e.g. "✓" or
+// "OK:".
+func Label(glyph string, label string) string {
+	if Enabled {
+		return label
+	}
+	return glyph
+}
+
+// Title returns the title of a form field. Plain output adds the
+// description, huh's accessible mode prints only the titles.
+func Title(title string, description string) string {
+	description = strings.TrimSpace(description)
+	if !Enabled || description == "" {
+		return title
+	}
+	return title + "\n" + description
+}
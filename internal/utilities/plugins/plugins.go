@@ -0,0 +1,265 @@
+package plugins
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities"
+	"github.com/nstr-dev/igitt/internal/utilities/config"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// Plugins are executables named igitt-<name>. Like git's own external
+// commands they can be written in any language.
+const pluginPrefix = "igitt-"
+const manifestExtension = ".yaml"
+
+var windowsExtensions = []string{".exe", ".cmd", ".bat", ".com"}
+
+// Manifest is read from igitt-<name>.yaml next to the plugin. Only plugins
+// with a manifest are shown in the interactive menu.
+type Manifest struct {
+	Name            string `yaml:"name"`
+	Description     string `yaml:"description"`
+	Icon            string `yaml:"icon"`
+	IconEmoji       string `yaml:"iconEmoji"`
+	IconNerdFont    string `yaml:"iconNerdfont"`
+	IconAscii       string `yaml:"iconAscii"`
+	Shortcut        string `yaml:"shortcut"`
+	InsideRepoOnly  bool   `yaml:"insideRepoOnly"`
+	OutsideRepoOnly bool   `yaml:"outsideRepoOnly"`
+}
+
+type Plugin struct {
+	Name          string
+	Path          string
+	Manifest      *Manifest
+	ManifestError error
+	// HiddenBy is the built-in command that has the same name
+	HiddenBy string
+}
+
+// ExitError is returned when a plugin ran and exited with a non-zero code.
+// igitt exits with the same code, the plugin has reported the problem.
+type ExitError struct {
+	Name     string
+	ExitCode int
+}
+
+func (e *ExitError) Error() string {
+	return locale.Get("plugins.exited", e.Name, e.ExitCode)
+}
+
+func getPluginName(fileName string) (string, bool) {
+	if !strings.HasPrefix(fileName, pluginPrefix) {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(fileName, pluginPrefix)
+
+	if runtime.GOOS == "windows" {
+		extension := strings.ToLower(filepath.Ext(name))
+		if !slices.Contains(windowsExtensions, extension) {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	if name == "" || strings.ContainsAny(name, ". ") {
+		return "", false
+	}
+
+	return name, true
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	return info.Mode().Perm()&0111 != 0
+}
+
+func readManifest(pluginPath string) (*Manifest, error) {
+	manifestPath := strings.TrimSuffix(pluginPath, filepath.Ext(pluginPath)) + manifestExtension
+
+	content, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestPath, err)
+	}
+
+	return &manifest, nil
+}
+
+// Discover finds the plugins in the plugins directory and on the PATH. The
+// first plugin with a name wins, so the plugins directory comes first.
+func Discover() []Plugin {
+	directories := append([]string{config.GetPluginsDir()}, filepath.SplitList(os.Getenv("PATH"))...)
+
+	var plugins []Plugin
+	seen := make(map[string]bool)
+
+	for _, directory := range directories {
+		if directory == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(directory)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, found := getPluginName(entry.Name())
+			if !found || seen[name] {
+				continue
+			}
+
+			path := filepath.Join(directory, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			seen[name] = true
+
+			plugin := Plugin{Name: name, Path: path}
+			plugin.Manifest, plugin.ManifestError = readManifest(path)
+			if plugin.ManifestError != nil {
+				logger.WarningLogger.Println("Invalid plugin manifest:", plugin.ManifestError)
+			}
+
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	slices.SortFunc(plugins, func(a Plugin, b Plugin) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return plugins
+}
+
+func (p Plugin) GetMenuName() string {
+	if p.Manifest != nil && p.Manifest.Name != "" {
+		return p.Manifest.Name
+	}
+	return p.Name
+}
+
+func (p Plugin) GetDescription() string {
+	if p.Manifest != nil && p.Manifest.Description != "" {
+		return p.Manifest.Description
+	}
+	return locale.Get("plugins.description", p.Path)
+}
+
+// getEnvironment passes the context of the current repository, so plugins
+// do not have to work it out again.
+func getEnvironment(plugin Plugin) []string {
+	environment := []string{
+		"IGITT_PLUGIN_NAME=" + plugin.Name,
+		"IGITT_CONFIG=" + config.GetConfigPath(false),
+	}
+
+	if executable, err := os.Executable(); err == nil {
+		environment = append(environment, "IGITT_EXECUTABLE="+executable)
+	}
+
+	repoRoot := utilities.GetRepoRoot()
+	if repoRoot == "" {
+		return environment
+	}
+
+	environment = append(environment, "IGITT_REPO_ROOT="+repoRoot)
+
+	if branch, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD").Output(); err == nil {
+		environment = append(environment, "IGITT_BRANCH="+strings.TrimSpace(string(branch)))
+	}
+
+	if repoConfigPath := config.GetRepoConfigPath(); repoConfigPath != "" {
+		environment = append(environment, "IGITT_REPO_CONFIG="+repoConfigPath)
+	}
+
+	return environment
+}
+
+// Run starts the plugin in the terminal with the given arguments. A dry run
+// only prints the command, igitt cannot know what the plugin would change.
+func (p Plugin) Run(arguments []string, dryRun bool) error {
+	if dryRun {
+		line := strings.Join(append([]string{p.Path}, arguments...), " ")
+		logger.InfoLogger.Println("Dry run, not running plugin:", line)
+		fmt.Println(color.HiBlackString("[dry-run]"), color.CyanString(line))
+		return nil
+	}
+
+	logger.InfoLogger.Println("Running plugin:", p.Path, arguments)
+
+	command := exec.Command(p.Path, arguments...)
+	command.Env = append(os.Environ(), getEnvironment(p)...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	err := command.Run()
+
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		logger.ErrorLogger.Printf("Plugin %s exited with code %d", p.Name, exitError.ExitCode())
+		return &ExitError{Name: p.Name, ExitCode: exitError.ExitCode()}
+	}
+	if err != nil {
+		// not wrapped, a plugin that cannot start must not look like missing git
+		return errors.New(locale.Get("plugins.startFailed", p.Name, err))
+	}
+
+	return nil
+}
+
+func PrintPlugins(plugins []Plugin) {
+	if len(plugins) == 0 {
+		fmt.Println(locale.Get("plugins.none", color.CyanString(pluginPrefix+"<name>"), color.BlueString(config.GetPluginsDir())))
+		return
+	}
+
+	for _, plugin := range plugins {
+		fmt.Printf("%s  %s\n", color.HiGreenString(plugin.Name), color.BlueString(plugin.Path))
+
+		switch {
+		case plugin.HiddenBy != "":
+			fmt.Printf("    %s\n", color.HiYellowString(locale.Get("plugins.hidden", plugin.HiddenBy)))
+		case plugin.ManifestError != nil:
+			fmt.Printf("    %s\n", color.HiRedString(locale.Get("plugins.invalidManifest", plugin.ManifestError)))
+		case plugin.Manifest != nil:
+			fmt.Printf("    %s\n", plugin.GetDescription())
+		default:
+			fmt.Printf("    %s\n", color.HiBlackString(locale.Get("plugins.noManifest")))
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package theme
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
+	"gopkg.in/yaml.v3"
+)
+
+const Custom = "custom"
+const Default = "catppuccin"
+
+var builtInThemes = map[string]func() *huh.Theme{
+	"catppuccin": huh.ThemeCatppuccin,
+	"charm":      huh.ThemeCharm,
+	"dracula":    huh.ThemeDracula,
+	"base16":     huh.ThemeBase16,
+	"base":       huh.ThemeBase,
+}
+
+// Color is a color for light and dark terminals. A single value like
+// "#ff0000" or "9" is used for both.
+type Color struct {
+	Light string `yaml:"light"`
+	Dark  string `yaml:"dark"`
+}
+
+func (c *Color) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		c.Light = node.Value
+		c.Dark = node.Value
+		return nil
+	}
+
+	type plain Color
+	return node.Decode((*plain)(c))
+}
+
+func (c Color) IsSet() bool {
+	return c.Light != "" || c.Dark != ""
+}
+
+// adaptive lets lipgloss pick the variant matching the terminal background.
+func (c Color) adaptive() lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{Light: c.Light, Dark: c.Dark}
+}
+
+type FieldColors struct {
+	Title       Color `yaml:"title"`
+	Description Color `yaml:"description"`
+	Text        Color `yaml:"text"`
+	Selected    Color `yaml:"selected"`
+	Border      Color `yaml:"border"`
+	Button      Color `yaml:"button"`
+	ButtonText  Color `yaml:"buttonText"`
+	Error       Color `yaml:"error"`
+}
+
+type ErrorColors struct {
+	Title   Color `yaml:"title"`
+	Message Color `yaml:"message"`
+	Border  Color `yaml:"border"`
+}
+
+// Palette is the format of a custom theme file. Status colors are keyed by
+// the two letter codes of git status, e.g. "M " or "??".
+type Palette struct {
+	Focused FieldColors      `yaml:"focused"`
+	Blurred FieldColors      `yaml:"blurred"`
+	Status  map[string]Color `yaml:"status"`
+	Error   ErrorColors      `yaml:"error"`
+}
+
+var activeName = Default
+var activePalette *Palette
+
+func GetBuiltInThemes() []string {
+	return []string{"catppuccin", "charm", "dracula", "base16", "base"}
+}
+
+// Load activates a built-in theme or, for "custom", the palette file. On
+// error the default theme stays active.
+func Load(name string, customThemePath string) error {
+	if name == Custom {
+		palette, err := ReadPalette(customThemePath)
+		if err != nil {
+			return err
+		}
+
+		activeName = Custom
+		activePalette = &palette
+		return nil
+	}
+
+	if _, found := builtInThemes[name]; !found {
+		return errors.New(locale.Get("theme.unknown", name))
+	}
+
+	activeName = name
+	activePalette = nil
+	return nil
+}
+
+func ReadPalette(path string) (Palette, error) {
+	var palette Palette
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return palette, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&palette); err != nil {
+		return palette, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return palette, nil
+}
+
+func GetHuhTheme() *huh.Theme {
+	if activePalette == nil {
+		return builtInThemes[activeName]()
+	}
+
+	t := huh.ThemeBase()
+	applyFieldColors(&t.Focused, activePalette.Focused)
+
+	t.Blurred = t.Focused
+	t.Blurred.Base = t.Focused.Base.BorderStyle(lipgloss.HiddenBorder())
+	t.Blurred.MultiSelectSelector = lipgloss.NewStyle().SetString("  ")
+	t.Blurred.NextIndicator = lipgloss.NewStyle()
+	t.Blurred.PrevIndicator = lipgloss.NewStyle()
+	applyFieldColors(&t.Blurred, activePalette.Blurred)
+
+	return t
+}
+
+func applyFieldColors(styles *huh.FieldStyles, colors FieldColors) {
+	if colors.Border.IsSet() {
+		styles.Base = styles.Base.BorderForeground(colors.Border.adaptive())
+	}
+
+	if colors.Title.IsSet() {
+		styles.Title = styles.Title.Foreground(colors.Title.adaptive()).Bold(true)
+		styles.NoteTitle = styles.NoteTitle.Foreground(colors.Title.adaptive()).Bold(true)
+		styles.Directory = styles.Directory.Foreground(colors.Title.adaptive())
+	}
+
+	if colors.Description.IsSet() {
+		styles.Description = styles.Description.Foreground(colors.Description.adaptive())
+		styles.TextInput.Placeholder = styles.TextInput.Placeholder.Foreground(colors.Description.adaptive())
+	}
+
+	if colors.Text.IsSet() {
+		styles.Option = styles.Option.Foreground(colors.Text.adaptive())
+		styles.UnselectedOption = styles.UnselectedOption.Foreground(colors.Text.adaptive())
+		styles.UnselectedPrefix = styles.UnselectedPrefix.Foreground(colors.Text.adaptive())
+		styles.TextInput.Text = styles.TextInput.Text.Foreground(colors.Text.adaptive())
+		styles.BlurredButton = styles.BlurredButton.Foreground(colors.Text.adaptive())
+	}
+
+	if colors.Selected.IsSet() {
+		styles.SelectSelector = styles.SelectSelector.Foreground(colors.Selected.adaptive())
+		styles.MultiSelectSelector = styles.MultiSelectSelector.Foreground(colors.Selected.adaptive())
+		styles.NextIndicator = styles.NextIndicator.Foreground(colors.Selected.adaptive())
+		styles.PrevIndicator = styles.PrevIndicator.Foreground(colors.Selected.adaptive())
+		styles.SelectedOption = styles.SelectedOption.Foreground(colors.Selected.adaptive())
+		styles.SelectedPrefix = styles.SelectedPrefix.Foreground(colors.Selected.adaptive())
+		styles.TextInput.Cursor = styles.TextInput.Cursor.Foreground(colors.Selected.adaptive())
+		styles.TextInput.Prompt = styles.TextInput.Prompt.Foreground(colors.Selected.adaptive())
+	}
+
+	if colors.Button.IsSet() {
+		styles.FocusedButton = styles.FocusedButton.Background(colors.Button.adaptive())
+	}
+
+	if colors.ButtonText.IsSet() {
+		styles.FocusedButton = styles.FocusedButton.Foreground(colors.ButtonText.adaptive())
+	}
+	styles.Next = styles.FocusedButton
+
+	if colors.Error.IsSet() {
+		styles.ErrorIndicator = styles.ErrorIndicator.Foreground(colors.Error.adaptive())
+		styles.ErrorMessage = styles.ErrorMessage.Foreground(colors.Error.adaptive())
+	}
+}
+
+func render(c Color, text string) string {
+	return lipgloss.NewStyle().Foreground(c.adaptive()).Render(text)
+}
+
+// Status colors the title of a git status code, using fallback unless the
+// custom palette sets a color for it.
+func Status(statusLetter string, fallback color.Attribute, text string) string {
+	if activePalette != nil {
+		if c, found := activePalette.Status[statusLetter]; found && c.IsSet() {
+			return render(c, text)
+		}
+	}
+
+	return color.New(fallback).Sprint(text)
+}
+
+func ErrorTitle(text string) string {
+	if activePalette != nil && activePalette.Error.Title.IsSet() {
+		return render(activePalette.Error.Title, text)
+	}
+	return color.HiRedString(text)
+}
+
+func ErrorMessage(text string) string {
+	if activePalette != nil && activePalette.Error.Message.IsSet() {
+		return render(activePalette.Error.Message, text)
+	}
+	return color.HiRedString(text)
+}
+
+func ErrorBorder(text string) string {
+	if activePalette != nil && activePalette.Error.Border.IsSet() {
+		return render(activePalette.Error.Border, text)
+	}
+	return color.HiBlackString(text)
+}
@@ -6,11 +6,22 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/giterrors"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 	"github.com/nstr-dev/igitt/internal/utilities/logger"
+	"github.com/nstr-dev/igitt/internal/utilities/plain"
+	"github.com/nstr-dev/igitt/internal/utilities/theme"
 )
 
 var spacing string = "=============================================================================\n\n"
 
+// ShowRawGitErrors prints git's original output below recognized errors.
+var ShowRawGitErrors bool
+
+// OfferErrorFixes lets the user run the suggested fixes of recognized errors
+// right away. It is enabled by the interactive mode.
+var OfferErrorFixes bool
+
 func RemoveLastEmptyLine(text string) string {
 	lines := strings.Split(text, "\n")
 
@@ -22,20 +33,54 @@ func RemoveLastEmptyLine(text string) string {
 	return strings.Join(lines, "\n")
 }
 
+// getErrorBorder returns the line around error messages, plain output leaves
+// it out so screen readers do not spell out a row of equals signs.
+func getErrorBorder() string {
+	if plain.Enabled {
+		return ""
+	}
+	return spacing
+}
+
 func PrintGeneralError(message string) {
-	fmt.Printf("\n%s", color.HiBlackString(spacing))
-	fmt.Printf("%s  There was an issue:\n\n%s\n", color.HiRedString("⚠"), color.HiRedString(message))
-	fmt.Printf("\n%s", color.HiBlackString(spacing))
+	fmt.Printf("\n%s", theme.ErrorBorder(getErrorBorder()))
+	fmt.Printf("%s  %s\n\n%s\n", theme.ErrorTitle(plain.Label("⚠", locale.Get("plain.error"))), locale.Get("error.general"), theme.ErrorMessage(message))
+	fmt.Printf("\n%s", theme.ErrorBorder(getErrorBorder()))
 }
 
 func PrintGitError(message string) {
-	fmt.Printf("\n%s", color.HiBlackString(spacing))
-	fmt.Printf("%s  There was an issue. Received following message from Git:\n\n%s\n", color.HiRedString("⚠"), color.HiRedString(message))
-	fmt.Printf("\n%s", color.HiBlackString(spacing))
+	// git did not run at all (e.g. it is not installed), the caller reports
+	// that on its own
+	if strings.TrimSpace(message) == "" {
+		return
+	}
+
+	knownError, recognized := giterrors.Match(message)
+
+	if !recognized {
+		fmt.Printf("\n%s", theme.ErrorBorder(getErrorBorder()))
+		fmt.Printf("%s  %s\n\n%s\n", theme.ErrorTitle(plain.Label("⚠", locale.Get("plain.error"))), locale.Get("error.git"), theme.ErrorMessage(message))
+		fmt.Printf("\n%s", theme.ErrorBorder(getErrorBorder()))
+		return
+	}
+
+	giterrors.Print(knownError, getErrorBorder())
+
+	if ShowRawGitErrors {
+		giterrors.PrintRawOutput(message)
+	} else if !OfferErrorFixes {
+		fmt.Printf("\n%s\n", color.HiBlackString(locale.Get("error.rawHint")))
+	}
+
+	fmt.Printf("\n%s", theme.ErrorBorder(getErrorBorder()))
+
+	if OfferErrorFixes {
+		giterrors.OfferSuggestions(knownError, message, ShowRawGitErrors)
+	}
 }
 
 func CheckIsRepo() bool {
-	byteOut, errOut := exec.Command("git", "rev-parse", "--is-inside-work-tree").CombinedOutput()
+	byteOut, errOut := giterrors.Command("rev-parse", "--is-inside-work-tree").CombinedOutput()
 
 	if errOut != nil {
 		if strings.Contains(string(byteOut), "not a git repository") {
@@ -52,3 +97,14 @@ func CheckIsRepo() bool {
 
 	return false
 }
+
+func GetRepoRoot() string {
+	byteOut, errOut := exec.Command("git", "rev-parse", "--show-toplevel").CombinedOutput()
+
+	if errOut != nil {
+		logger.ErrorLogger.Println("Error getting repository root:", errOut, string(byteOut))
+		return ""
+	}
+
+	return strings.TrimSpace(string(byteOut))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/fatih/color"
+	"github.com/nstr-dev/igitt/internal/utilities/locale"
 )
 
 func Spacing(amount int) {
@@ -19,23 +20,23 @@ func PrintWelcomeMessage() {
 	underline := color.New(color.Underline).SprintfFunc()
 
 	Spacing(1)
-	titleMessage("Welcome to Igitt - Interactive Git in the Terminal!")
+	titleMessage(locale.Get("welcome.title"))
 	Spacing(2)
 
-	heading("Getting Started")
+	heading(locale.Get("welcome.gettingStarted"))
 	Spacing(2)
-	fmt.Printf("To enter the interactive experience, run %s without any arguments.", command("igitt"))
+	fmt.Print(locale.Get("welcome.interactive", command("igitt")))
 	Spacing(1)
-	fmt.Printf("For a list of available commands, run %s.", command("igitt --help"))
+	fmt.Print(locale.Get("welcome.help", command("igitt --help")))
 	Spacing(2)
 
-	heading("Alias")
+	heading(locale.Get("welcome.alias"))
 	Spacing(2)
-	fmt.Printf("To use the alias %s instead of %s, run following command: %s", command("igt"), command("igitt"), underline(command("igitt igt")))
+	fmt.Print(locale.Get("welcome.aliasHint", command("igt"), command("igitt"), underline(command("igitt igt"))))
 	Spacing(2)
 
-	heading("Configuration")
+	heading(locale.Get("welcome.configuration"))
 	Spacing(2)
-	fmt.Printf("To configure Igitt (e.g. to change how icons are displayed and which commands are shown), run %s.", command("igitt config"))
+	fmt.Print(locale.Get("welcome.configurationHint", command("igitt config")))
 	Spacing(3)
 }